@@ -14,13 +14,18 @@ const maxtrials = 10
 
 // GenerateTop returns nsamples of function evaluations that exceed the minOF
 func GenerateTop(fp string, eval func(u []float64, i int) float64, s sampler.Set, nsamples int, minOF float64) {
+	GenerateTopFunc(fp, eval, s, nsamples, func(f float64) bool { return f > minOF })
+}
+
+// GenerateTopFunc returns nsamples of function evaluations that satisfy the acceptance rule (e.g., glue.Acceptable)
+func GenerateTopFunc(fp string, eval func(u []float64, i int) float64, s sampler.Set, nsamples int, accept func(f float64) bool) {
 	tim := time.Now()
 	cnt, iter := 0, 0
 	coll := make([][]float64, 0, nsamples*maxtrials)
 	for {
 		uFinal, rFinal := GenerateSamples(eval, s.Ndim, nsamples, runtime.GOMAXPROCS(0))
 		for i, f := range rFinal {
-			if f > minOF {
+			if accept(f) {
 				lst := make([]float64, s.Ndim+1)
 				lst[0] = f
 				for ii, v := range uFinal[i] {
@@ -60,3 +65,23 @@ func saveGob(fp string, s sampler.Set, coll [][]float64) {
 		fmt.Println(err)
 	}
 }
+
+// ReadTop returns the sampling set and collection of samples saved by GenerateTop,
+// where every row of the collection is the function evaluation followed by the sample point u
+func ReadTop(fp string) (sampler.Set, [][]float64, error) {
+	var s sampler.Set
+	var coll [][]float64
+	f, err := os.Open(fp)
+	if err != nil {
+		return s, nil, err
+	}
+	defer f.Close()
+	dec := gob.NewDecoder(f)
+	if err := dec.Decode(&s); err != nil {
+		return s, nil, err
+	}
+	if err := dec.Decode(&coll); err != nil {
+		return s, nil, err
+	}
+	return s, coll, nil
+}
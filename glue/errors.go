@@ -0,0 +1,6 @@
+package glue
+
+import "errors"
+
+// ErrEmptyEnsemble is returned (wrapped) when no simulation carries a positive weight, test using errors.Is
+var ErrEmptyEnsemble = errors.New("no simulation of positive weight")
@@ -0,0 +1,130 @@
+// evaluate.go contains measures of the quality of GLUE prediction bounds.
+// Time steps with missing observations are excluded.
+// see Jin, X., C.-Y. Xu, Q. Zhang, V.P. Singh, 2010. Parameter and modeling uncertainty simulated by GLUE and a formal Bayesian method for a conceptual hydrological model. Journal of Hydrology 383. pp.147-155.
+
+package glue
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// checkBounds returns an error if the prediction bounds and observations differ in length
+func checkBounds(fn string, obs, lower, upper []float64) error {
+	if len(lower) != len(obs) || len(upper) != len(obs) {
+		return fmt.Errorf("glue.%s: bounds of length %d, %d given for %d observations", fn, len(lower), len(upper), len(obs))
+	}
+	return nil
+}
+
+// ContainingRatio returns the percentage of observations bracketed by the lower and upper prediction bounds
+func ContainingRatio(obs, lower, upper []float64) (float64, error) {
+	if err := checkBounds("ContainingRatio", obs, lower, upper); err != nil {
+		return 0., err
+	}
+	n, c := 0, 0
+	for i, o := range obs {
+		if !valid(o) {
+			continue
+		}
+		n++
+		if o >= lower[i] && o <= upper[i] {
+			c++
+		}
+	}
+	return 100. * float64(c) / float64(n), nil
+}
+
+// ARIL returns the average relative interval length, the mean width of the prediction bounds relative to the observation.
+// Time steps with an observation of zero are excluded.
+func ARIL(obs, lower, upper []float64) (float64, error) {
+	if err := checkBounds("ARIL", obs, lower, upper); err != nil {
+		return 0., err
+	}
+	n, s := 0, 0.
+	for i, o := range obs {
+		if !valid(o) || o == 0. {
+			continue
+		}
+		n++
+		s += (upper[i] - lower[i]) / o
+	}
+	return s / float64(n), nil
+}
+
+// Sharpness returns the mean width of the prediction bounds
+func Sharpness(obs, lower, upper []float64) (float64, error) {
+	if err := checkBounds("Sharpness", obs, lower, upper); err != nil {
+		return 0., err
+	}
+	n, s := 0, 0.
+	for i, o := range obs {
+		if !valid(o) {
+			continue
+		}
+		n++
+		s += upper[i] - lower[i]
+	}
+	return s / float64(n), nil
+}
+
+// CRPS returns the continuous ranked probability score averaged over all time steps,
+// where the predictive distribution is the ensemble of simulated series sims[run][timestep]
+// weighted by (GLUE) likelihood weights w. Optimal value is 0.0, in units of the observations.
+// An error wrapping ErrEmptyEnsemble is returned when no weight is positive.
+// see Hersbach, H., 2000. Decomposition of the Continuous Ranked Probability Score for Ensemble Prediction Systems. Weather and Forecasting 15. pp.559-570.
+func CRPS(obs []float64, sims [][]float64, w []float64) (float64, error) {
+	if len(w) != len(sims) {
+		return 0., fmt.Errorf("glue.CRPS: %d weights given for %d simulations", len(w), len(sims))
+	}
+	g := make(GLUE, 0, len(sims))
+	for i, x := range sims {
+		if len(x) != len(obs) {
+			return 0., fmt.Errorf("glue.CRPS: simulation %d has %d time steps, expecting %d", i, len(x), len(obs))
+		}
+		if w[i] > 0. {
+			g = append(g, GLUEi{Likelihood: w[i]})
+		}
+	}
+	if len(g) == 0 {
+		return 0., fmt.Errorf("glue.CRPS: %w", ErrEmptyEnsemble)
+	}
+	n, s := 0, 0.
+	for t, o := range obs {
+		if !valid(o) {
+			continue
+		}
+		k := 0
+		for i, x := range sims {
+			if w[i] > 0. {
+				g[k].Value = x[t]
+				k++
+			}
+		}
+		n++
+		s += g.crps(o)
+	}
+	return s / float64(n), nil
+}
+
+// crps returns the continuous ranked probability score of observation y given the weighted ensemble:
+// E|X-y| - E|X-X'|/2
+func (g GLUE) crps(y float64) float64 {
+	s := make(GLUE, len(g))
+	copy(s, g)
+	sort.Sort(s)
+	c := 0.
+	for _, v := range s {
+		c += v.Likelihood
+	}
+	e1, e2, wc, xc := 0., 0., 0., 0.
+	for _, v := range s {
+		w := v.Likelihood / c
+		e1 += w * math.Abs(v.Value-y)
+		e2 += w * (v.Value*wc - xc) // sum over pairs i<j of w_i*w_j*(x_j-x_i)
+		wc += w
+		xc += w * v.Value
+	}
+	return e1 - e2
+}
@@ -0,0 +1,19 @@
+package glue
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestCRPS checks CRPS against its closed form for a two-member ensemble, and its error on an empty ensemble
+func TestCRPS(t *testing.T) {
+	obs, sims := []float64{1., 2.}, [][]float64{{0., 2.}, {2., 4.}}
+	// t=0: E|X-1| = 1, E|X-X'|/2 = .5; t=1: E|X-2| = 1, E|X-X'|/2 = .5
+	if c, err := CRPS(obs, sims, []float64{1., 1.}); err != nil || math.Abs(c-.5) > 1e-12 {
+		t.Errorf("CRPS = %v, %v", c, err)
+	}
+	if c, err := CRPS(obs, sims, []float64{0., -1.}); !errors.Is(err, ErrEmptyEnsemble) {
+		t.Errorf("CRPS of no positive weight = %v, %v", c, err)
+	}
+}
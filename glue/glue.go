@@ -2,7 +2,10 @@ package glue
 
 // Generalized Likelihood Uncertainty Estimator
 
-import "sort"
+import (
+	"math"
+	"sort"
+)
 
 // GLUEsortInterface interfaces sort.Sort
 type GLUEsortInterface interface {
@@ -24,7 +27,7 @@ func (g GLUE) Less(i, j int) bool {
 }
 
 func (g GLUE) Swap(i, j int) {
-	g[i].Value, g[j].Value = g[j].Value, g[i].Value
+	g[i], g[j] = g[j], g[i]
 }
 
 func (g GLUE) Len() int {
@@ -36,23 +39,113 @@ func (g GLUE) Partition(i int) (left GLUEsortInterface, right GLUEsortInterface)
 	return GLUE(g[:i]), GLUE(g[i+1:])
 }
 
+// P5o95 returns the likelihood-weighted 5th and 95th percentiles.
+// Deprecated: use Quantiles
 func (g GLUE) P5o95() (float64, float64) {
-	// must be sorted
-	c := 0.
+	q := g.Quantiles(.05, .95)
+	return q[0], q[1]
+}
+
+// Sort orders g by Value, keeping Likelihood paired with Value
+func (g GLUE) Sort() { sort.Sort(g) }
+
+// sorted returns a sorted copy of g, excluding values of zero likelihood, and its total likelihood
+func (g GLUE) sorted() (GLUE, float64) {
+	s, c := make(GLUE, 0, len(g)), 0.
 	for _, v := range g {
-		c += v.Likelihood
+		if v.Likelihood > 0. {
+			s = append(s, v)
+			c += v.Likelihood
+		}
+	}
+	sort.Sort(s)
+	return s, c
+}
+
+// positions returns the cumulative likelihood at the centre of every sorted value's weight
+func (g GLUE) positions(c float64) []float64 {
+	f, cp := make([]float64, len(g)), 0.
+	for i, v := range g {
+		f[i] = (cp + v.Likelihood/2.) / c
+		cp += v.Likelihood
+	}
+	return f
+}
+
+// Quantiles returns the likelihood-weighted values at cumulative probabilities p,
+// linearly interpolating between values. Values are ordered internally (g is left unchanged).
+// Probabilities beyond the centre of the first (last) value's weight return the minimum (maximum) value.
+func (g GLUE) Quantiles(p ...float64) []float64 {
+	q := make([]float64, len(p))
+	s, c := g.sorted()
+	if len(s) == 0 || c <= 0. {
+		for k := range q {
+			q[k] = math.NaN()
+		}
+		return q
+	}
+	f, n := s.positions(c), len(s)
+	for k, pp := range p {
+		i := sort.SearchFloat64s(f, pp)
+		switch {
+		case i == 0:
+			q[k] = s[0].Value
+		case i == n:
+			q[k] = s[n-1].Value
+		default:
+			q[k] = s[i-1].Value + (pp-f[i-1])/(f[i]-f[i-1])*(s[i].Value-s[i-1].Value)
+		}
 	}
-	p, o5, o95 := 0., -9999., 0.
+	return q
+}
+
+// CDF returns the likelihood-weighted cumulative probability of value x,
+// the inverse of Quantiles
+func (g GLUE) CDF(x float64) float64 {
+	s, c := g.sorted()
+	if len(s) == 0 || c <= 0. {
+		return math.NaN()
+	}
+	n := len(s)
+	if x < s[0].Value {
+		return 0.
+	}
+	if x >= s[n-1].Value {
+		return 1.
+	}
+	f := s.positions(c)
+	i := sort.Search(n, func(i int) bool { return s[i].Value > x })
+	return f[i-1] + (x-s[i-1].Value)/(s[i].Value-s[i-1].Value)*(f[i]-f[i-1])
+}
+
+// Mean returns the likelihood-weighted mean of members with positive likelihood (as Quantiles and CDF),
+// NaN when there are none
+func (g GLUE) Mean() float64 {
+	m, c := 0., 0.
 	for _, v := range g {
-		p += v.Likelihood / c
-		if o5 == -9999. && p > .05 {
-			o5 = v.Value
-			continue
+		if v.Likelihood > 0. {
+			m += v.Likelihood * v.Value
+			c += v.Likelihood
 		}
-		if p > .95 {
-			o95 = v.Value
-			break
+	}
+	if c <= 0. {
+		return math.NaN()
+	}
+	return m / c
+}
+
+// Variance returns the likelihood-weighted variance of members with positive likelihood,
+// NaN when there are none
+func (g GLUE) Variance() float64 {
+	m, s, c := g.Mean(), 0., 0.
+	for _, v := range g {
+		if v.Likelihood > 0. {
+			s += v.Likelihood * (v.Value - m) * (v.Value - m)
+			c += v.Likelihood
 		}
 	}
-	return o5, o95
+	if c <= 0. {
+		return math.NaN()
+	}
+	return s / c
 }
@@ -0,0 +1,24 @@
+package glue
+
+import (
+	"math"
+	"testing"
+)
+
+// TestGLUEMoments checks that Mean and Variance ignore members of non-positive likelihood, as do Quantiles and CDF
+func TestGLUEMoments(t *testing.T) {
+	g := GLUE{{1., 1.}, {1., 3.}, {0., 100.}, {-2., 50.}}
+	if m := g.Mean(); m != 2. {
+		t.Errorf("Mean = %v", m)
+	}
+	if v := g.Variance(); v != 1. {
+		t.Errorf("Variance = %v", v)
+	}
+	if f := g.CDF(g.Mean()); f != .5 {
+		t.Errorf("CDF(Mean) = %v", f)
+	}
+	g = GLUE{{0., 1.}, {-1., 3.}}
+	if m, v := g.Mean(), g.Variance(); !math.IsNaN(m) || !math.IsNaN(v) {
+		t.Errorf("no positive likelihood: Mean, Variance = %v, %v", m, v)
+	}
+}
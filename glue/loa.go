@@ -0,0 +1,154 @@
+// loa.go implements the limits of acceptability framework, where every observation is given
+// an acceptable interval and a run is behavioural only if its simulation falls within the
+// limits at all (or a given fraction of) time steps.
+// see Beven, K., 2006. A manifesto for the equifinality thesis. Journal of Hydrology 320. pp.18-36.
+// and Blazkova, S., and K. Beven, 2009. A limits of acceptability approach to model evaluation and uncertainty estimation in flood frequency estimation by continuous simulation: Skalka catchment, Czech Republic. Water Resources Research 45. W00B16.
+
+package glue
+
+import (
+	"fmt"
+	"math"
+)
+
+// Limits holds the limits of acceptability about every observation
+type Limits struct {
+	Obs, Lower, Upper []float64
+	Fraction          float64 // fraction of (valid) time steps that must be within limits for a run to be behavioural
+}
+
+// LimitsSummary holds the run-level summary of limits of acceptability scores
+type LimitsSummary struct {
+	Within      float64 // fraction of time steps within limits
+	MeanAbs     float64 // mean absolute score
+	MaxAbs      float64 // maximum absolute score
+	Likelihood  float64 // mean triangular weight (1-|score|, zero beyond limits)
+	Behavioural bool
+}
+
+// NewLimits constructor, fraction = 1 requires all time steps to be within limits
+func NewLimits(obs, lower, upper []float64, fraction float64) (*Limits, error) {
+	if len(lower) != len(obs) || len(upper) != len(obs) {
+		return nil, fmt.Errorf("glue.NewLimits: limits of length %d, %d given for %d observations", len(lower), len(upper), len(obs))
+	}
+	if fraction <= 0. || fraction > 1. {
+		return nil, fmt.Errorf("glue.NewLimits: invalid fraction %v", fraction)
+	}
+	for i, o := range obs {
+		if valid(o) && (lower[i] > o || upper[i] < o) {
+			return nil, fmt.Errorf("glue.NewLimits: limits [%v, %v] at time step %d do not bracket observation %v", lower[i], upper[i], i, o)
+		}
+	}
+	return &Limits{Obs: obs, Lower: lower, Upper: upper, Fraction: fraction}, nil
+}
+
+// NewRelativeLimits constructor, setting limits to within ± rel (i.e., 0.2 = 20%) of every observation
+func NewRelativeLimits(obs []float64, rel, fraction float64) (*Limits, error) {
+	lo, up := make([]float64, len(obs)), make([]float64, len(obs))
+	for i, o := range obs {
+		d := math.Abs(rel * o)
+		lo[i], up[i] = o-d, o+d
+	}
+	return NewLimits(obs, lo, up, fraction)
+}
+
+// Scores returns the normalized score of the simulation at every time step: 0 at the observation,
+// -1 at the lower limit and +1 at the upper limit; values beyond [-1,1] lie outside the limits.
+// Missing time steps return NaN.
+func (l *Limits) Scores(sim []float64) ([]float64, error) {
+	if len(sim) != len(l.Obs) {
+		return nil, fmt.Errorf("glue.Limits.Scores: simulation has %d time steps, expecting %d", len(sim), len(l.Obs))
+	}
+	sc := make([]float64, len(l.Obs))
+	for i, o := range l.Obs {
+		switch {
+		case !valid(o) || !valid(sim[i]):
+			sc[i] = math.NaN()
+		case sim[i] == o:
+			sc[i] = 0.
+		case sim[i] < o:
+			sc[i] = (sim[i] - o) / (o - l.Lower[i])
+		default:
+			sc[i] = (sim[i] - o) / (l.Upper[i] - o)
+		}
+	}
+	return sc, nil
+}
+
+// Summarize returns the run-level summary of the simulation scores
+func (l *Limits) Summarize(sim []float64) (LimitsSummary, error) {
+	var s LimitsSummary
+	sc, err := l.Scores(sim)
+	if err != nil {
+		return s, err
+	}
+	n, w := 0, 0
+	for _, v := range sc {
+		if math.IsNaN(v) {
+			continue
+		}
+		a := math.Abs(v)
+		n++
+		s.MeanAbs += a
+		s.MaxAbs = math.Max(s.MaxAbs, a)
+		if a <= 1. {
+			w++
+			s.Likelihood += 1. - a
+		}
+	}
+	if n == 0 {
+		return LimitsSummary{math.NaN(), math.NaN(), math.NaN(), math.NaN(), false}, nil
+	}
+	s.Within = float64(w) / float64(n)
+	s.MeanAbs /= float64(n)
+	s.Likelihood /= float64(n)
+	s.Behavioural = s.Within >= l.Fraction
+	return s, nil
+}
+
+// Likelihood returns the limits of acceptability likelihood of the simulation [0,1],
+// -1 if the simulation is non-behavioural, or NaN if it does not match the observations in length.
+// Used as the evaluation function with Acceptable as the acceptance rule in GenerateTopFunc.
+// A behavioural simulation may have a likelihood of 0 (every score within limits at ±1).
+func (l *Limits) Likelihood(sim []float64) float64 {
+	s, err := l.Summarize(sim)
+	if err != nil {
+		return math.NaN()
+	}
+	if !s.Behavioural {
+		return -1.
+	}
+	return s.Likelihood
+}
+
+// Acceptable returns true if likelihood f was returned from a behavioural simulation (see Limits.Likelihood)
+func Acceptable(f float64) bool {
+	return f >= 0.
+}
+
+// Run performs GLUE using the limits of acceptability as the rejection criterion
+// on simulated series sims[run][timestep], returning the lower and upper prediction quantiles.
+// Behavioural runs of zero likelihood are given zero weight.
+func (l *Limits) Run(sims [][]float64, lower, upper float64) (*Result, error) {
+	if lower < 0. || upper > 1. || lower >= upper {
+		return nil, fmt.Errorf("glue.Limits.Run: invalid prediction quantiles %v, %v", lower, upper)
+	}
+	lk, b, wb := make([]float64, len(sims)), make([]bool, len(sims)), make([]bool, len(sims))
+	for i, s := range sims {
+		if len(s) != len(l.Obs) {
+			return nil, fmt.Errorf("glue.Limits.Run: simulation %d has %d time steps, expecting %d", i, len(s), len(l.Obs))
+		}
+		lk[i] = l.Likelihood(s)
+		b[i] = Acceptable(lk[i])
+		wb[i] = b[i] && lk[i] > 0.
+	}
+	wt, err := Weights(lk, wb)
+	if err != nil {
+		return nil, err
+	}
+	lo, md, up, err := Bounds(sims, wt, lower, upper)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Likelihood: lk, Weights: wt, Behavioural: b, Lower: lo, Median: md, Upper: up}, nil
+}
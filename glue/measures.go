@@ -0,0 +1,241 @@
+// measures.go contains goodness-of-fit and likelihood measures of paired observed and simulated series.
+// Observations or simulations that are NaN or set to Missing are excluded from evaluation; NaN is returned
+// when no valid pairs remain or the series differ in length.
+// Orientation: NSE, LogNSE, KGE, KGEprime and InverseErrorVariance are likelihood-form (larger is better,
+// positive for acceptable fits); RMSE and PBIAS are errors (optimal value 0.0), use Negate to rank them;
+// Gaussian and AR1 are log-likelihoods (usually negative), to be weighted with Workflow.Log (LogWeights).
+
+package glue
+
+import "math"
+
+// Missing value indicator
+const Missing = -9999.
+
+// Measure is an informal likelihood measure of a simulated series given
+// observations, where larger values indicate a better fit (except where noted)
+type Measure func(obs, sim []float64) float64
+
+// Negate returns measure m as -|m|, such that error measures with an optimal value of 0.0
+// (RMSE, PBIAS) rank larger values as better fits, e.g., for TopFraction rejection or GenerateTop.
+// Negated measures are not positive, and cannot be used as likelihood weights.
+func Negate(m Measure) Measure {
+	return func(obs, sim []float64) float64 {
+		return -math.Abs(m(obs, sim))
+	}
+}
+
+// pairs returns the observed and simulated values where both are valid, none if the series differ in length
+func pairs(obs, sim []float64) ([]float64, []float64) {
+	if len(sim) != len(obs) {
+		return nil, nil
+	}
+	o, s := make([]float64, 0, len(obs)), make([]float64, 0, len(obs))
+	for i, v := range obs {
+		if valid(v) && valid(sim[i]) {
+			o = append(o, v)
+			s = append(s, sim[i])
+		}
+	}
+	return o, s
+}
+
+func valid(v float64) bool {
+	return v != Missing && !math.IsNaN(v)
+}
+
+func meanSD(x []float64) (float64, float64) {
+	m, s := 0., 0.
+	for _, v := range x {
+		m += v
+	}
+	m /= float64(len(x))
+	for _, v := range x {
+		s += (v - m) * (v - m)
+	}
+	return m, math.Sqrt(s / float64(len(x)))
+}
+
+// NSE returns the Nash-Sutcliffe (1970) model efficiency
+func NSE(obs, sim []float64) float64 {
+	o, s := pairs(obs, sim)
+	if len(o) == 0 {
+		return math.NaN()
+	}
+	om, _ := meanSD(o)
+	n, d := 0., 0.
+	for i := range o {
+		n += (s[i] - o[i]) * (s[i] - o[i])
+		d += (o[i] - om) * (o[i] - om)
+	}
+	return 1. - n/d
+}
+
+// LogNSE returns the Nash-Sutcliffe efficiency of log-transformed values, emphasizing low flows.
+// A small constant (1/100th of the mean observation) is added to avoid zero values
+// see Pushpalatha, R., C. Perrin, N. Le Moine, V. Andréassian, 2012. A review of efficiency criteria suitable for evaluating low-flow simulations. Journal of Hydrology 420-421. pp.171-182.
+func LogNSE(obs, sim []float64) float64 {
+	o, s := pairs(obs, sim)
+	if len(o) == 0 {
+		return math.NaN()
+	}
+	om, _ := meanSD(o)
+	e := om / 100.
+	lo, ls := make([]float64, len(o)), make([]float64, len(o))
+	for i := range o {
+		if o[i]+e <= 0. || s[i]+e <= 0. {
+			return math.NaN()
+		}
+		lo[i] = math.Log(o[i] + e)
+		ls[i] = math.Log(s[i] + e)
+	}
+	return NSE(lo, ls)
+}
+
+// KGE returns the Kling-Gupta efficiency
+// see Gupta, H.V., H. Kling, K.K. Yilmaz, G.F. Martinez, 2009. Decomposition of the mean squared error and NSE performance criteria: Implications for improving hydrological modelling. Journal of Hydrology 377. pp.80-91.
+func KGE(obs, sim []float64) float64 {
+	r, a, b := kgeComponents(obs, sim)
+	return 1. - math.Sqrt((r-1.)*(r-1.)+(a-1.)*(a-1.)+(b-1.)*(b-1.))
+}
+
+// KGEprime returns the modified Kling-Gupta efficiency (KGE'), where the variability
+// ratio is replaced by the ratio of coefficients of variation to avoid cross-correlation with bias
+// see Kling, H., M. Fuchs, M. Paulin, 2012. Runoff conditions in the upper Danube basin under an ensemble of climate change scenarios. Journal of Hydrology 424-425. pp.264-277.
+func KGEprime(obs, sim []float64) float64 {
+	r, a, b := kgeComponents(obs, sim)
+	g := a / b
+	return 1. - math.Sqrt((r-1.)*(r-1.)+(g-1.)*(g-1.)+(b-1.)*(b-1.))
+}
+
+// kgeComponents returns the correlation, variability ratio and bias ratio
+func kgeComponents(obs, sim []float64) (float64, float64, float64) {
+	o, s := pairs(obs, sim)
+	if len(o) == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	om, osd := meanSD(o)
+	sm, ssd := meanSD(s)
+	c := 0.
+	for i := range o {
+		c += (o[i] - om) * (s[i] - sm)
+	}
+	r := c / float64(len(o)) / osd / ssd
+	return r, ssd / osd, sm / om
+}
+
+// PBIAS returns the percent bias (%) of simulated volumes, optimal value is 0.0
+func PBIAS(obs, sim []float64) float64 {
+	o, s := pairs(obs, sim)
+	if len(o) == 0 {
+		return math.NaN()
+	}
+	n, d := 0., 0.
+	for i := range o {
+		n += s[i] - o[i]
+		d += o[i]
+	}
+	if d == 0. {
+		return math.NaN()
+	}
+	return 100. * n / d
+}
+
+// RMSE returns the root-mean-square error, optimal value is 0.0
+func RMSE(obs, sim []float64) float64 {
+	o, s := pairs(obs, sim)
+	if len(o) == 0 {
+		return math.NaN()
+	}
+	e := 0.
+	for i := range o {
+		e += (s[i] - o[i]) * (s[i] - o[i])
+	}
+	return math.Sqrt(e / float64(len(o)))
+}
+
+// InverseErrorVariance returns the inverse error variance measure of Beven and Binley (1992)
+// with shaping factor n (n=1 returns the inverse error variance; higher values
+// concentrate likelihood weight on the better performing runs)
+func InverseErrorVariance(n float64) Measure {
+	return func(obs, sim []float64) float64 {
+		o, s := pairs(obs, sim)
+		if len(o) == 0 {
+			return math.NaN()
+		}
+		e := 0.
+		for i := range o {
+			e += (s[i] - o[i]) * (s[i] - o[i])
+		}
+		return math.Pow(e/float64(len(o)), -n)
+	}
+}
+
+// Gaussian returns the (formal) log-likelihood of the simulation assuming residuals are
+// independent and normally distributed with zero mean and constant variance (estimated by maximum likelihood)
+func Gaussian(obs, sim []float64) float64 {
+	o, s := pairs(obs, sim)
+	if len(o) == 0 {
+		return math.NaN()
+	}
+	n, e := float64(len(o)), 0.
+	for i := range o {
+		e += (s[i] - o[i]) * (s[i] - o[i])
+	}
+	return -n / 2. * (math.Log(2.*math.Pi*e/n) + 1.)
+}
+
+// AR1 returns the (formal) log-likelihood of the simulation assuming residuals follow
+// a first-order autoregressive process with normally distributed innovations. The lag-1 autocorrelation
+// and innovation variance are estimated from the residuals. Missing values restart the process.
+// see Sorooshian, S. and J.A. Dracup, 1980. Stochastic parameter estimation procedures for hydrologic rainfall-runoff models: correlated and heteroscedastic error cases. Water Resources Research 16(2). pp.430-442.
+func AR1(obs, sim []float64) float64 {
+	if len(sim) != len(obs) {
+		return math.NaN()
+	}
+	e := make([]float64, len(obs))
+	for i, o := range obs {
+		if valid(o) && valid(sim[i]) {
+			e[i] = sim[i] - o
+		} else {
+			e[i] = math.NaN()
+		}
+	}
+
+	// lag-1 autocorrelation of residuals
+	c0, c1 := 0., 0.
+	for i, v := range e {
+		if math.IsNaN(v) {
+			continue
+		}
+		c0 += v * v
+		if i > 0 && !math.IsNaN(e[i-1]) {
+			c1 += v * e[i-1]
+		}
+	}
+	if c0 == 0. {
+		return math.NaN()
+	}
+	phi := c1 / c0
+	if math.Abs(phi) >= 1. {
+		return math.Inf(-1)
+	}
+
+	// innovations, the first value of every uninterrupted sequence is scaled to the stationary variance
+	n, ss, starts := 0., 0., 0.
+	for i, v := range e {
+		if math.IsNaN(v) {
+			continue
+		}
+		if i > 0 && !math.IsNaN(e[i-1]) {
+			a := v - phi*e[i-1]
+			ss += a * a
+		} else {
+			ss += (1. - phi*phi) * v * v
+			starts++
+		}
+		n++
+	}
+	s2 := ss / n
+	return -n/2.*math.Log(2.*math.Pi*s2) + starts/2.*math.Log(1.-phi*phi) - ss/2./s2
+}
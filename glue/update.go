@@ -0,0 +1,109 @@
+// update.go combines likelihood weights of a previous GLUE analysis with
+// likelihoods evaluated on a new calibration period for the same parameter sets
+// see Beven, K. and A. Binley, 1992. The future of distributed models: model calibration and uncertainty prediction. Hydrological Processes 6. pp.279-298.
+
+package glue
+
+import (
+	"fmt"
+	"math"
+)
+
+// Combination enum type used to combine prior and new likelihoods
+type Combination int
+
+// Combination enums
+const (
+	Multiplicative   Combination = iota // Bayes equation: posterior ∝ prior × likelihood
+	WeightedAdditive                    // posterior ∝ wp×prior + (1-wp)×likelihood
+	FuzzyMin                            // fuzzy intersection: posterior ∝ min(prior, likelihood)
+)
+
+// String needed to return a Combination type as string
+func (c Combination) String() string {
+	return [...]string{"multiplicative", "weighted-additive", "fuzzy-min"}[c]
+}
+
+// Update returns posterior weights (summing to unity) from prior weights and new likelihoods
+// of the same parameter sets, combined using operator op, along with the effective sample size
+// of the posterior. wp [0,1] is the weight given to the prior when op is WeightedAdditive.
+// Prior weights and likelihoods are normalized before combining; both must be non-negative.
+func Update(prior, likelihood []float64, op Combination, wp float64) ([]float64, float64, error) {
+	if len(prior) != len(likelihood) {
+		return nil, 0., fmt.Errorf("glue.Update: %d prior weights given with %d likelihoods", len(prior), len(likelihood))
+	}
+	p, err := normalize(prior)
+	if err != nil {
+		return nil, 0., fmt.Errorf("glue.Update: prior %v", err)
+	}
+	l, err := normalize(likelihood)
+	if err != nil {
+		return nil, 0., fmt.Errorf("glue.Update: likelihood %v", err)
+	}
+
+	w := make([]float64, len(p))
+	switch op {
+	case Multiplicative:
+		for i := range w {
+			w[i] = p[i] * l[i]
+		}
+	case WeightedAdditive:
+		if wp < 0. || wp > 1. {
+			return nil, 0., fmt.Errorf("glue.Update: invalid prior weight %v", wp)
+		}
+		for i := range w {
+			w[i] = wp*p[i] + (1.-wp)*l[i]
+		}
+	case FuzzyMin:
+		for i := range w {
+			w[i] = math.Min(p[i], l[i])
+		}
+	default:
+		return nil, 0., fmt.Errorf("glue.Update: unknown combination operator %d", op)
+	}
+	if w, err = normalize(w); err != nil {
+		return nil, 0., fmt.Errorf("glue.Update: posterior %v (no parameter set is supported by both prior and likelihood)", err)
+	}
+	return w, EffectiveSampleSize(w), nil
+}
+
+// EffectiveSampleSize returns Kish's effective sample size of weights w: (Σw)²/Σw²
+func EffectiveSampleSize(w []float64) float64 {
+	s, s2 := 0., 0.
+	for _, v := range w {
+		s += v
+		s2 += v * v
+	}
+	if s2 == 0. {
+		return 0.
+	}
+	return s * s / s2
+}
+
+// TopLikelihoods splits a collection of samples saved by GenerateTop (see ReadTop)
+// into likelihoods (the first column) and the sample points u
+func TopLikelihoods(coll [][]float64) ([]float64, [][]float64) {
+	l, u := make([]float64, len(coll)), make([][]float64, len(coll))
+	for i, c := range coll {
+		l[i] = c[0]
+		u[i] = c[1:]
+	}
+	return l, u
+}
+
+func normalize(x []float64) ([]float64, error) {
+	w, s := make([]float64, len(x)), 0.
+	for i, v := range x {
+		if v < 0. || math.IsNaN(v) {
+			return nil, fmt.Errorf("value %d is invalid (%v)", i, v)
+		}
+		s += v
+	}
+	if s == 0. {
+		return nil, fmt.Errorf("sums to zero")
+	}
+	for i, v := range x {
+		w[i] = v / s
+	}
+	return w, nil
+}
@@ -0,0 +1,204 @@
+// workflow.go performs the Generalized Likelihood Uncertainty Estimation procedure
+// see Beven, K. and A. Binley, 1992. The future of distributed models: model calibration and uncertainty prediction. Hydrological Processes 6. pp.279-298.
+
+package glue
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Rejection enum type used to separate behavioural from non-behavioural runs
+type Rejection int
+
+// Rejection enums
+const (
+	Threshold   Rejection = iota // runs with a likelihood greater than Limit are behavioural
+	TopFraction                  // the best Limit fraction (0,1] of runs are behavioural
+)
+
+// String needed to return a Rejection type as string
+func (r Rejection) String() string {
+	return [...]string{"threshold", "top-fraction"}[r]
+}
+
+// Workflow holds the settings of a GLUE analysis
+type Workflow struct {
+	Measure      Measure   // informal likelihood measure
+	Rejection    Rejection // behavioural rejection criterion
+	Limit        float64   // likelihood threshold or top fraction retained
+	Lower, Upper float64   // prediction quantiles of the uncertainty bounds
+	Log          bool      // Measure returns log-likelihoods (e.g., Gaussian, AR1), weighted by LogWeights
+}
+
+// Result holds the outcome of a GLUE analysis
+type Result struct {
+	Likelihood           []float64 // likelihood of every run
+	Weights              []float64 // normalized likelihood weights (zero for non-behavioural runs)
+	Behavioural          []bool
+	Lower, Median, Upper []float64 // likelihood-weighted prediction quantiles at every time step
+}
+
+// New Workflow constructor, defaults to the 5-95% prediction bounds
+func New(m Measure, rej Rejection, limit float64) *Workflow {
+	return &Workflow{Measure: m, Rejection: rej, Limit: limit, Lower: .05, Upper: .95}
+}
+
+// Run performs GLUE given observations obs and an ensemble of simulated series sims[run][timestep]
+func (w *Workflow) Run(obs []float64, sims [][]float64) (*Result, error) {
+	if w.Lower < 0. || w.Lower > .5 || w.Upper < .5 || w.Upper > 1. {
+		return nil, fmt.Errorf("glue.Run: invalid prediction quantiles %v, %v", w.Lower, w.Upper)
+	}
+	l, err := Likelihoods(obs, sims, w.Measure)
+	if err != nil {
+		return nil, err
+	}
+	b, err := Behavioural(l, w.Rejection, w.Limit)
+	if err != nil {
+		return nil, err
+	}
+	weights := Weights
+	if w.Log {
+		weights = LogWeights
+	}
+	wt, err := weights(l, b)
+	if err != nil {
+		return nil, err
+	}
+	lo, md, up, err := Bounds(sims, wt, w.Lower, w.Upper)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Likelihood: l, Weights: wt, Behavioural: b, Lower: lo, Median: md, Upper: up}, nil
+}
+
+// Likelihoods evaluates measure m of every simulated series sims[run][timestep] against observations obs
+func Likelihoods(obs []float64, sims [][]float64, m Measure) ([]float64, error) {
+	l := make([]float64, len(sims))
+	for i, s := range sims {
+		if len(s) != len(obs) {
+			return nil, fmt.Errorf("glue.Likelihoods: simulation %d has %d time steps, expecting %d", i, len(s), len(obs))
+		}
+		l[i] = m(obs, s)
+	}
+	return l, nil
+}
+
+// Behavioural flags runs as behavioural according to the given rejection criterion
+func Behavioural(l []float64, rej Rejection, limit float64) ([]bool, error) {
+	b := make([]bool, len(l))
+	switch rej {
+	case Threshold:
+		for i, v := range l {
+			b[i] = v > limit
+		}
+	case TopFraction:
+		if limit <= 0. || limit > 1. {
+			return nil, fmt.Errorf("glue.Behavioural: invalid top fraction %v", limit)
+		}
+		ix := make([]int, len(l))
+		for i := range ix {
+			ix[i] = i
+		}
+		sort.SliceStable(ix, func(i, j int) bool { return l[ix[i]] > l[ix[j]] })
+		n := int(limit*float64(len(l)) + .5)
+		if n == 0 {
+			n = 1
+		}
+		for _, i := range ix[:n] {
+			b[i] = true
+		}
+	default:
+		return nil, fmt.Errorf("glue.Behavioural: unknown rejection criterion %d", rej)
+	}
+	return b, nil
+}
+
+// Weights returns likelihoods of behavioural runs normalized to sum to unity,
+// non-behavioural runs are given zero weight
+func Weights(l []float64, behavioural []bool) ([]float64, error) {
+	w, s := make([]float64, len(l)), 0.
+	for i, v := range l {
+		if !behavioural[i] {
+			continue
+		}
+		if v <= 0. {
+			return nil, fmt.Errorf("glue.Weights: behavioural run %d has a non-positive likelihood (%v), choose a greater threshold or rescale the measure", i, v)
+		}
+		w[i] = v
+		s += v
+	}
+	if s == 0. {
+		return nil, fmt.Errorf("glue.Weights: no behavioural runs")
+	}
+	for i := range w {
+		w[i] /= s
+	}
+	return w, nil
+}
+
+// LogWeights returns log-likelihoods of behavioural runs as likelihoods normalized to sum to unity,
+// scaled by the greatest log-likelihood to avoid underflow; non-behavioural runs are given zero weight
+func LogWeights(l []float64, behavioural []bool) ([]float64, error) {
+	mx := math.Inf(-1)
+	for i, v := range l {
+		if !behavioural[i] {
+			continue
+		}
+		if math.IsNaN(v) || math.IsInf(v, 1) {
+			return nil, fmt.Errorf("glue.LogWeights: behavioural run %d has an invalid log-likelihood (%v)", i, v)
+		}
+		mx = math.Max(mx, v)
+	}
+	if math.IsInf(mx, -1) {
+		return nil, fmt.Errorf("glue.LogWeights: no behavioural runs")
+	}
+	w, s := make([]float64, len(l)), 0.
+	for i, v := range l {
+		if behavioural[i] {
+			w[i] = math.Exp(v - mx)
+			s += w[i]
+		}
+	}
+	for i := range w {
+		w[i] /= s
+	}
+	return w, nil
+}
+
+// Bounds returns the lower, median and upper likelihood-weighted
+// prediction quantiles at every time step of simulated series sims[run][timestep]
+func Bounds(sims [][]float64, w []float64, lower, upper float64) ([]float64, []float64, []float64, error) {
+	if len(w) != len(sims) {
+		return nil, nil, nil, fmt.Errorf("glue.Bounds: %d weights given for %d simulations", len(w), len(sims))
+	}
+	if len(sims) == 0 {
+		return nil, nil, nil, nil
+	}
+	nt := len(sims[0])
+	for i, s := range sims {
+		if len(s) != nt {
+			return nil, nil, nil, fmt.Errorf("glue.Bounds: simulation %d has %d time steps, expecting %d", i, len(s), nt)
+		}
+	}
+	lo, md, up := make([]float64, nt), make([]float64, nt), make([]float64, nt)
+	g := make(GLUE, 0, len(sims))
+	for i := range sims {
+		if w[i] > 0. {
+			g = append(g, GLUEi{Likelihood: w[i]})
+		}
+	}
+	for t := 0; t < nt; t++ {
+		k := 0
+		for i, s := range sims {
+			if w[i] > 0. {
+				g[k].Value = s[t]
+				k++
+			}
+		}
+		q := g.Quantiles(lower, .5, upper)
+		lo[t], md[t], up[t] = q[0], q[1], q[2]
+	}
+	return lo, md, up, nil
+}
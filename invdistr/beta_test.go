@@ -0,0 +1,87 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// relErr returns the relative difference of got from want
+func relErr(got, want float64) float64 {
+	if want == 0. {
+		return math.Abs(got)
+	}
+	return math.Abs(got/want - 1.)
+}
+
+// TestBetaFTable checks Beta quantiles against tabulated critical values of the F distribution,
+// where X ~ Beta(d1/2, d2/2) gives F = d2 X / d1 (1-X)
+// see Table 26.9 of: Abramowitz, M. and I.A. Stegun, 1972. Handbook of Mathematical Functions. Dover, New York. 1046pp.
+func TestBetaFTable(t *testing.T) {
+	for _, c := range []struct {
+		d1, d2, p, f float64
+	}{
+		{1., 1., .95, 161.4476},
+		{1., 10., .95, 4.964603},
+		{1., 10., .99, 10.04429},
+		{2., 10., .95, 4.102821},
+		{5., 10., .95, 3.325835},
+		{10., 10., .95, 2.978237},
+	} {
+		b, err := NewBeta(c.d1/2., c.d2/2.)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := c.d1 * c.f / (c.d1*c.f + c.d2)
+		if x := b.Inv(c.p); relErr(x, want) > 1e-6 {
+			t.Errorf("Beta(%v, %v).Inv(%v) = %v, want %v", c.d1/2., c.d2/2., c.p, x, want)
+		}
+		if p := b.CDF(want); math.Abs(p-c.p) > 1e-6 {
+			t.Errorf("Beta(%v, %v).CDF(%v) = %v, want %v", c.d1/2., c.d2/2., want, p, c.p)
+		}
+	}
+}
+
+// TestBetaClosedForm checks Beta quantiles of small shape parameters and extreme u against closed forms:
+// Beta(a,1) = u^(1/a), Beta(1,b) = 1-(1-u)^(1/b) and the arcsine Beta(.5,.5) = sin²(πu/2)
+func TestBetaClosedForm(t *testing.T) {
+	for _, c := range []struct {
+		a, b float64
+		inv  func(u float64) float64
+	}{
+		{.2, 1., func(u float64) float64 { return math.Pow(u, 5.) }},
+		{.5, 1., func(u float64) float64 { return u * u }},
+		{1., .3, func(u float64) float64 { return -math.Expm1(math.Log1p(-u) / .3) }},
+		{.5, .5, func(u float64) float64 { s := math.Sin(math.Pi * u / 2.); return s * s }},
+		{2., 1., math.Sqrt},
+	} {
+		b, err := NewBeta(c.a, c.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []float64{1e-10, 1e-4, .05, .5, .95, 1. - 1e-4, 1. - 1e-10} {
+			want := c.inv(u)
+			if x := b.Inv(u); relErr(x, want) > 1e-8 {
+				t.Errorf("Beta(%v, %v).Inv(%v) = %v, want %v", c.a, c.b, u, x, want)
+			}
+			if want < 1. {
+				if p := b.CDF(want); relErr(p, u) > 1e-8 {
+					t.Errorf("Beta(%v, %v).CDF(%v) = %v, want %v", c.a, c.b, want, p, u)
+				}
+			}
+		}
+	}
+}
+
+// TestPERT checks that the PERT mode and mean are recovered
+func TestPERT(t *testing.T) {
+	b, err := NewPERT(1., 3., 9.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := 1. + 8.*b.Mean(); relErr(m, (1.+4.*3.+9.)/6.) > 1e-12 {
+		t.Errorf("PERT mean = %v, want %v", m, (1.+4.*3.+9.)/6.)
+	}
+	if _, err := NewPERT(1., 10., 9.); err == nil {
+		t.Error("NewPERT: mode beyond maximum accepted")
+	}
+}
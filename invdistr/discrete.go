@@ -0,0 +1,321 @@
+// discrete.go returns discrete probability distributions from u[0,1]: discrete uniform, Poisson,
+// binomial and weighted categorical. Each returns integer values (category indices for Categorical)
+// in parameter space (use with a Map where Low=0, High=1). Inverses are monotone step functions of u
+// such that stratified samples remain stratified: every distribution maps u in [F(k-1), F(k)) to k,
+// i.e., the smallest k with F(k) > u (u = 1 maps to the greatest k). PDF returns the probability mass at integer x.
+// see pg.308 of: Law, A.M., 2007. Simulation Modeling and Analysis. McGraw-Hill, fourth ed. New York. 768pp.
+
+package invdistr
+
+import (
+	"fmt"
+	"math"
+)
+
+// discreteInv returns the smallest integer k in [lo, hi] with cdf(k) > u, searching from k0
+func discreteInv(cdf func(k int) float64, k0, lo, hi int, u float64) int {
+	k := k0
+	if k < lo {
+		k = lo
+	} else if k > hi {
+		k = hi
+	}
+	for k < hi && cdf(k) <= u {
+		k++
+	}
+	for k > lo && cdf(k-1) > u {
+		k--
+	}
+	return k
+}
+
+// isDiscrete returns true for distributions of integer values
+func isDiscrete(d Mapper) bool {
+	switch d.(type) {
+	case *DiscreteUniform, *Poisson, *Binomial, *Categorical:
+		return true
+	}
+	return false
+}
+
+// isInt returns true if x is an integer
+func isInt(x float64) bool { return x == math.Trunc(x) }
+
+// DiscreteUniform sampling distribution
+type DiscreteUniform struct {
+	lo, hi int
+}
+
+// NewDiscreteUniform constructor of equally-likely integers lo, lo+1, ..., hi
+func NewDiscreteUniform(lo, hi int) (*DiscreteUniform, error) {
+	if lo > hi {
+		return nil, fmt.Errorf("invdistr.NewDiscreteUniform: invalid arguments lo, hi = %v, %v: %w", lo, hi, ErrInvalidBounds)
+	}
+	return &DiscreteUniform{lo: lo, hi: hi}, nil
+}
+
+// Inv : inverse function
+func (t *DiscreteUniform) Inv(u float64) float64 {
+	k := t.lo + int(math.Floor(u*float64(t.hi-t.lo+1)))
+	if k > t.hi {
+		return float64(t.hi)
+	} else if k < t.lo {
+		return float64(t.lo)
+	}
+	return float64(k)
+}
+
+// PDF : probability mass function
+func (t *DiscreteUniform) PDF(x float64) float64 {
+	if !isInt(x) || x < float64(t.lo) || x > float64(t.hi) {
+		return 0.
+	}
+	return 1. / float64(t.hi-t.lo+1)
+}
+
+// CDF : cumulative distribution function
+func (t *DiscreteUniform) CDF(x float64) float64 {
+	return math.Min(math.Max((math.Floor(x)-float64(t.lo)+1.)/float64(t.hi-t.lo+1), 0.), 1.)
+}
+
+// Mean of the distribution
+func (t *DiscreteUniform) Mean() float64 { return float64(t.lo+t.hi) / 2. }
+
+// Variance of the distribution
+func (t *DiscreteUniform) Variance() float64 {
+	n := float64(t.hi - t.lo + 1)
+	return (n*n - 1.) / 12.
+}
+
+// Support returns the range of the distribution
+func (t *DiscreteUniform) Support() (float64, float64) { return float64(t.lo), float64(t.hi) }
+
+// Poisson sampling distribution
+type Poisson struct {
+	lambda float64 // mean
+}
+
+// NewPoisson constructor with mean lambda
+func NewPoisson(lambda float64) (*Poisson, error) {
+	if lambda <= 0. || math.IsInf(lambda, 0) {
+		return nil, fmt.Errorf("invdistr.NewPoisson: invalid argument lambda = %v: %w", lambda, ErrInvalidShape)
+	}
+	return &Poisson{lambda: lambda}, nil
+}
+
+// cdf returns P(X <= k)
+func (t *Poisson) cdf(k int) float64 {
+	if k < 0 {
+		return 0.
+	}
+	return incGammaQ(float64(k+1), t.lambda)
+}
+
+// Inv : inverse function
+func (t *Poisson) Inv(u float64) float64 {
+	if u >= 1. {
+		return math.Inf(1)
+	}
+	k0 := int(math.Max(math.Floor(t.lambda+math.Sqrt(t.lambda)*normQuantile(math.Max(u, 1e-16))), 0.))
+	return float64(discreteInv(t.cdf, k0, 0, math.MaxInt32, u))
+}
+
+// PDF : probability mass function
+func (t *Poisson) PDF(x float64) float64 {
+	if !isInt(x) || x < 0. {
+		return 0.
+	}
+	lg, _ := math.Lgamma(x + 1.)
+	return math.Exp(x*math.Log(t.lambda) - t.lambda - lg)
+}
+
+// CDF : cumulative distribution function
+func (t *Poisson) CDF(x float64) float64 {
+	if x >= math.MaxInt32 {
+		return 1.
+	}
+	return t.cdf(int(math.Floor(x)))
+}
+
+// Mean of the distribution
+func (t *Poisson) Mean() float64 { return t.lambda }
+
+// Variance of the distribution
+func (t *Poisson) Variance() float64 { return t.lambda }
+
+// Support returns the range of the distribution
+func (t *Poisson) Support() (float64, float64) { return 0., math.Inf(1) }
+
+// Binomial sampling distribution
+type Binomial struct {
+	n int     // number of trials
+	p float64 // probability of success
+}
+
+// NewBinomial constructor of the number of successes in n trials, each with probability p
+func NewBinomial(n int, p float64) (*Binomial, error) {
+	if n < 1 || p < 0. || p > 1. {
+		return nil, fmt.Errorf("invdistr.NewBinomial: invalid arguments n, p = %v, %v: %w", n, p, ErrInvalidShape)
+	}
+	return &Binomial{n: n, p: p}, nil
+}
+
+// cdf returns P(X <= k)
+func (t *Binomial) cdf(k int) float64 {
+	switch {
+	case k < 0:
+		return 0.
+	case k >= t.n:
+		return 1.
+	}
+	return incBeta(float64(t.n-k), float64(k+1), 1.-t.p)
+}
+
+// Inv : inverse function
+func (t *Binomial) Inv(u float64) float64 {
+	switch t.p {
+	case 0.:
+		return 0.
+	case 1.:
+		return float64(t.n)
+	}
+	k0 := int(math.Floor(t.Mean() + math.Sqrt(t.Variance())*normQuantile(math.Min(math.Max(u, 1e-16), 1.-1e-16))))
+	return float64(discreteInv(t.cdf, k0, 0, t.n, u))
+}
+
+// PDF : probability mass function
+func (t *Binomial) PDF(x float64) float64 {
+	if !isInt(x) || x < 0. || x > float64(t.n) {
+		return 0.
+	}
+	switch t.p {
+	case 0.:
+		if x == 0. {
+			return 1.
+		}
+		return 0.
+	case 1.:
+		if x == float64(t.n) {
+			return 1.
+		}
+		return 0.
+	}
+	n := float64(t.n)
+	return math.Exp(-lnBeta(x+1., n-x+1.) - math.Log(n+1.) + x*math.Log(t.p) + (n-x)*math.Log(1.-t.p))
+}
+
+// CDF : cumulative distribution function
+func (t *Binomial) CDF(x float64) float64 {
+	if x >= float64(t.n) {
+		return 1.
+	} else if t.p == 0. && x >= 0. {
+		return 1.
+	} else if t.p == 1. {
+		return 0.
+	}
+	return t.cdf(int(math.Floor(x)))
+}
+
+// Mean of the distribution
+func (t *Binomial) Mean() float64 { return float64(t.n) * t.p }
+
+// Variance of the distribution
+func (t *Binomial) Variance() float64 { return float64(t.n) * t.p * (1. - t.p) }
+
+// Support returns the range of the distribution
+func (t *Binomial) Support() (float64, float64) { return 0., float64(t.n) }
+
+// Categorical sampling distribution
+type Categorical struct {
+	p, cum []float64 // probabilities and cumulative probabilities of each category
+}
+
+// NewCategorical constructor of category indices 0, 1, ..., len(weights)-1,
+// each with probability proportional to its (non-negative) weight
+func NewCategorical(weights []float64) (*Categorical, error) {
+	n := len(weights)
+	if n == 0 {
+		return nil, fmt.Errorf("invdistr.NewCategorical: no categories given: %w", ErrDimensionMismatch)
+	}
+	s := 0.
+	for i, w := range weights {
+		if w < 0. || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("invdistr.NewCategorical: invalid weight [%d] = %v: %w", i, w, ErrInvalidShape)
+		}
+		s += w
+	}
+	if s <= 0. {
+		return nil, fmt.Errorf("invdistr.NewCategorical: weights sum to zero: %w", ErrInvalidShape)
+	}
+	t := &Categorical{p: make([]float64, n), cum: make([]float64, n)}
+	c := 0.
+	for i, w := range weights {
+		t.p[i] = w / s
+		c += t.p[i]
+		t.cum[i] = c
+	}
+	t.cum[n-1] = 1.
+	return t, nil
+}
+
+// Probabilities returns the probability of each category
+func (t *Categorical) Probabilities() []float64 {
+	p := make([]float64, len(t.p))
+	copy(p, t.p)
+	return p
+}
+
+// Inv : inverse function, returning the category index
+func (t *Categorical) Inv(u float64) float64 {
+	for i, c := range t.cum {
+		if u < c && t.p[i] > 0. {
+			return float64(i)
+		}
+	}
+	for i := len(t.p) - 1; i >= 0; i-- { // u = 1: last non-zero category
+		if t.p[i] > 0. {
+			return float64(i)
+		}
+	}
+	return 0.
+}
+
+// PDF : probability mass function
+func (t *Categorical) PDF(x float64) float64 {
+	if !isInt(x) || x < 0. || x >= float64(len(t.p)) {
+		return 0.
+	}
+	return t.p[int(x)]
+}
+
+// CDF : cumulative distribution function
+func (t *Categorical) CDF(x float64) float64 {
+	switch {
+	case x < 0.:
+		return 0.
+	case x >= float64(len(t.p)-1):
+		return 1.
+	}
+	return t.cum[int(math.Floor(x))]
+}
+
+// Mean of the distribution (of category indices)
+func (t *Categorical) Mean() float64 {
+	m := 0.
+	for i, p := range t.p {
+		m += float64(i) * p
+	}
+	return m
+}
+
+// Variance of the distribution (of category indices)
+func (t *Categorical) Variance() float64 {
+	m, v := t.Mean(), 0.
+	for i, p := range t.p {
+		v += (float64(i) - m) * (float64(i) - m) * p
+	}
+	return v
+}
+
+// Support returns the range of the distribution
+func (t *Categorical) Support() (float64, float64) { return 0., float64(len(t.p) - 1) }
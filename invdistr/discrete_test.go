@@ -0,0 +1,40 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDiscreteBoundaries checks that every discrete distribution maps u in [F(k-1), F(k)) to k
+func TestDiscreteBoundaries(t *testing.T) {
+	du, _ := NewDiscreteUniform(2, 5)
+	po, _ := NewPoisson(3.)
+	bi, _ := NewBinomial(6, .3)
+	ca, _ := NewCategorical([]float64{1., 0., 2., 1.})
+	for _, d := range []Distribution{du, po, bi, ca} {
+		lo, hi := d.Support()
+		if math.IsInf(hi, 1) {
+			hi = 20.
+		}
+		for k := lo; k <= hi; k++ {
+			if d.PDF(k) == 0. {
+				continue
+			}
+			f0, f1 := d.CDF(k-1.), d.CDF(k)
+			for _, u := range []float64{f0, (f0 + f1) / 2., math.Nextafter(f1, 0.)} {
+				if x := d.Inv(u); x != k {
+					t.Errorf("%T.Inv(%v) = %v, want %v", d, u, x, k)
+				}
+			}
+		}
+		if x := d.Inv(0.); x != lo && d.PDF(lo) > 0. {
+			t.Errorf("%T.Inv(0) = %v, want %v", d, x, lo)
+		}
+	}
+	if x := ca.Inv(.25); x != 2. { // category 1 has no weight
+		t.Errorf("Categorical.Inv(.25) = %v, want 2", x)
+	}
+	if x := ca.Inv(1.); x != 3. {
+		t.Errorf("Categorical.Inv(1) = %v, want 3", x)
+	}
+}
@@ -0,0 +1,10 @@
+package invdistr
+
+import "errors"
+
+// Errors returned (wrapped) by the distribution constructors, test using errors.Is
+var (
+	ErrInvalidBounds     = errors.New("invalid bounds")
+	ErrInvalidShape      = errors.New("invalid shape parameter")
+	ErrDimensionMismatch = errors.New("dimension mismatch")
+)
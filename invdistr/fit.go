@@ -0,0 +1,568 @@
+// fit.go fits distributions to data by the method of moments, maximum likelihood or L-moments,
+// and to expert estimates (e.g., P10/P50/P90) by quantile matching. Fits return the
+// fitted distribution as a Map along with goodness-of-fit statistics.
+// see Law, A.M., 2007. Simulation Modeling and Analysis. McGraw-Hill, fourth ed. New York. 768pp.
+// and Hosking, J.R.M. and J.R. Wallis, 1997. Regional Frequency Analysis: An Approach Based on L-Moments. Cambridge University Press. 224pp.
+
+package invdistr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// FitMethod is the method of parameter estimation
+type FitMethod int
+
+// fitting methods
+const (
+	MethodOfMoments FitMethod = iota
+	MaximumLikelihood
+	LMoments
+	QuantileMatching
+)
+
+func (m FitMethod) String() string {
+	return [...]string{"MethodOfMoments", "MaximumLikelihood", "LMoments", "QuantileMatching"}[m]
+}
+
+// Fit is a fitted distribution with its goodness-of-fit statistics
+type Fit struct {
+	Map    *Map // the fitted distribution
+	Method FitMethod
+	N, K   int // number of data (or quantiles) and fitted parameters
+
+	// data fits
+	LogLikelihood, AIC float64
+	KS                 float64 // Kolmogorov-Smirnov statistic
+	AD                 float64 // Anderson-Darling statistic
+
+	// quantile-matching fits
+	QuantileRMSE float64 // root-mean-squared difference between the fitted and given quantiles
+}
+
+// Distribution returns the fitted distribution
+func (f *Fit) Distribution() Distribution { return f.Map.Distr.(Distribution) }
+
+// newFit evaluates the goodness-of-fit of a k-parameter distribution d fit to data
+func newFit(d Distribution, low, high float64, method FitMethod, k int, data []float64) *Fit {
+	f := &Fit{Map: &Map{Low: low, High: high, Distr: d}, Method: method, N: len(data), K: k}
+	x := make([]float64, len(data))
+	copy(x, data)
+	sort.Float64s(x)
+	n := float64(len(x))
+	c := make([]float64, len(x))
+	for i, v := range x {
+		f.LogLikelihood += math.Log(f.Map.PDF(v))
+		c[i] = math.Min(math.Max(f.Map.CDF(v), 1e-300), 1.-1e-16)
+		f.KS = math.Max(f.KS, math.Max(c[i]-float64(i)/n, float64(i+1)/n-c[i]))
+	}
+	f.AIC = 2.*float64(k) - 2.*f.LogLikelihood
+	s := 0.
+	for i := range c {
+		s += float64(2*i+1) * (math.Log(c[i]) + math.Log(1.-c[len(c)-1-i]))
+	}
+	f.AD = -n - s/n
+	return f
+}
+
+// checkData returns an error if data has fewer than nmin values, or any value not exceeding min
+func checkData(fn string, data []float64, nmin int, min float64) error {
+	if len(data) < nmin {
+		return fmt.Errorf("invdistr.%s: at least %d data required, %d given: %w", fn, nmin, len(data), ErrDimensionMismatch)
+	}
+	for i, v := range data {
+		if math.IsNaN(v) || math.IsInf(v, 0) || v <= min {
+			return fmt.Errorf("invdistr.%s: invalid datum [%d] = %v", fn, i, v)
+		}
+	}
+	return nil
+}
+
+// transform returns f(x) for each element of x
+func transform(x []float64, f func(float64) float64) []float64 {
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = f(v)
+	}
+	return y
+}
+
+// mle returns the maximum likelihood estimate of distribution d(theta) to data, starting from theta0
+// with initial simplex steps dtheta
+func mle(fn string, d func([]float64) (Distribution, error), data, theta0, dtheta []float64) (Distribution, error) {
+	nll := func(theta []float64) float64 {
+		t, err := d(theta)
+		if err != nil {
+			return math.Inf(1)
+		}
+		s := 0.
+		for _, v := range data {
+			s -= math.Log(t.PDF(v))
+		}
+		if math.IsNaN(s) {
+			return math.Inf(1)
+		}
+		return s
+	}
+	if math.IsInf(nll(theta0), 1) {
+		return nil, fmt.Errorf("invdistr.%s: maximum likelihood starting point is infeasible", fn)
+	}
+	return d(nelderMead(nll, theta0, dtheta))
+}
+
+// unsupported returns an error for a fitting method not available to the distribution
+func unsupported(fn string, method FitMethod) error {
+	return fmt.Errorf("invdistr.%s: unsupported method %v", fn, method)
+}
+
+// FitNormal fits the Normal distribution to data
+func FitNormal(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitNormal", data, 2, math.Inf(-1)); err != nil {
+		return nil, err
+	}
+	var d *Normal
+	var err error
+	switch method {
+	case MethodOfMoments:
+		m, s, _ := SampleMoments(data)
+		d, err = NewNormal(m, s)
+	case MaximumLikelihood:
+		m, s, _ := SampleMoments(data)
+		n := float64(len(data))
+		d, err = NewNormal(m, s*math.Sqrt((n-1.)/n))
+	case LMoments:
+		l1, l2, _, _ := SampleLMoments(data)
+		d, err = NewNormal(l1, l2*math.Sqrt(math.Pi))
+	default:
+		return nil, unsupported("FitNormal", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 2, data), nil
+}
+
+// FitLogNormal fits the LogNormal distribution to (positive) data. Maximum likelihood and
+// L-moment estimates are those of the Normal distribution fit to ln(data).
+func FitLogNormal(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitLogNormal", data, 2, 0.); err != nil {
+		return nil, err
+	}
+	var d *LogNormal
+	var err error
+	switch method {
+	case MethodOfMoments:
+		m, s, _ := SampleMoments(data)
+		d, err = NewLogNormalMoments(m, s)
+	case MaximumLikelihood, LMoments:
+		var f *Fit
+		if f, err = FitNormal(transform(data, math.Log), method); err != nil {
+			return nil, err
+		}
+		n := f.Distribution().(*Normal)
+		d, err = NewLogNormal(n.mu, n.sigma)
+	default:
+		return nil, unsupported("FitLogNormal", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 2, data), nil
+}
+
+// FitGamma fits the Gamma distribution to (positive) data. The maximum likelihood shape
+// is solved by Newton's method; the L-moment estimate uses the rational approximation
+// of Hosking and Wallis (1997, pg.197).
+func FitGamma(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitGamma", data, 2, 0.); err != nil {
+		return nil, err
+	}
+	var k, theta float64
+	switch method {
+	case MethodOfMoments:
+		m, s, _ := SampleMoments(data)
+		k, theta = m*m/s/s, s*s/m
+	case MaximumLikelihood:
+		m, ml := 0., 0.
+		for _, v := range data {
+			m += v
+			ml += math.Log(v)
+		}
+		n := float64(len(data))
+		m /= n
+		s := math.Log(m) - ml/n
+		if s <= 0. {
+			return nil, fmt.Errorf("invdistr.FitGamma: data have no variance")
+		}
+		k = (3. - s + math.Sqrt((s-3.)*(s-3.)+24.*s)) / 12. / s // Minka (2002) initial estimate
+		for i := 0; i < 100; i++ {
+			dk := (math.Log(k) - digamma(k) - s) / (1./k - trigamma(k))
+			k -= dk
+			if math.Abs(dk) < 1e-12*k {
+				break
+			}
+		}
+		theta = m / k
+	case LMoments:
+		l1, l2, _, _ := SampleLMoments(data)
+		t := l2 / l1
+		if t < .5 {
+			z := math.Pi * t * t
+			k = (1. - .3080*z) / (z - .05812*z*z + .01765*z*z*z)
+		} else {
+			z := 1. - t
+			k = (.7213*z - .5947*z*z) / (1. - 2.1817*z + 1.2113*z*z)
+		}
+		theta = l1 / k
+	default:
+		return nil, unsupported("FitGamma", method)
+	}
+	d, err := NewGamma(k, theta)
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 2, data), nil
+}
+
+// FitWeibull fits the Weibull distribution to (positive) data. The moment and maximum
+// likelihood shape parameters are solved numerically.
+func FitWeibull(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitWeibull", data, 2, 0.); err != nil {
+		return nil, err
+	}
+	var k, lambda float64
+	switch method {
+	case MethodOfMoments:
+		m, s, _ := SampleMoments(data)
+		cv2 := s * s / m / m
+		k = bisect(func(k float64) float64 {
+			g1, _ := math.Lgamma(1. + 1./k)
+			g2, _ := math.Lgamma(1. + 2./k)
+			return cv2 - math.Exp(g2-2.*g1) + 1.
+		}, .02, 500.)
+		lambda = m / math.Gamma(1.+1./k)
+	case MaximumLikelihood:
+		xmax := 0.
+		for _, v := range data {
+			xmax = math.Max(xmax, v)
+		}
+		x := transform(data, func(v float64) float64 { return v / xmax }) // scaled for stability
+		lx := transform(x, math.Log)
+		ml := 0.
+		for _, v := range lx {
+			ml += v
+		}
+		ml /= float64(len(x))
+		sums := func(k float64) (s0, s1 float64) {
+			for i, v := range x {
+				xk := math.Pow(v, k)
+				s0 += xk
+				s1 += xk * lx[i]
+			}
+			return
+		}
+		k = bisect(func(k float64) float64 {
+			s0, s1 := sums(k)
+			return s1/s0 - 1./k - ml
+		}, .02, 500.)
+		s0, _ := sums(k)
+		lambda = xmax * math.Pow(s0/float64(len(x)), 1./k)
+	case LMoments:
+		l1, l2, _, _ := SampleLMoments(data)
+		k = -math.Ln2 / math.Log(1.-l2/l1)
+		lambda = l1 / math.Gamma(1.+1./k)
+	default:
+		return nil, unsupported("FitWeibull", method)
+	}
+	d, err := NewWeibull(k, lambda)
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 2, data), nil
+}
+
+// FitGumbel fits the Gumbel distribution to data. The maximum likelihood scale is solved numerically.
+func FitGumbel(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitGumbel", data, 2, math.Inf(-1)); err != nil {
+		return nil, err
+	}
+	var d *Gumbel
+	var err error
+	switch method {
+	case MethodOfMoments:
+		m, s, _ := SampleMoments(data)
+		d, err = NewGumbelMoments(m, s)
+	case MaximumLikelihood:
+		m, s, _ := SampleMoments(data)
+		xmin := math.Inf(1)
+		for _, v := range data {
+			xmin = math.Min(xmin, v)
+		}
+		sums := func(b float64) (s0, s1 float64) {
+			for _, v := range data {
+				e := math.Exp(-(v - xmin) / b)
+				s0 += e
+				s1 += v * e
+			}
+			return
+		}
+		b := bisect(func(b float64) float64 {
+			s0, s1 := sums(b)
+			return b - m + s1/s0
+		}, 1e-6*s, 10.*s)
+		s0, _ := sums(b)
+		d, err = NewGumbel(xmin-b*math.Log(s0/float64(len(data))), b)
+	case LMoments:
+		l1, l2, _, _ := SampleLMoments(data)
+		d, err = NewGumbelLMoments(l1, l2)
+	default:
+		return nil, unsupported("FitGumbel", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 2, data), nil
+}
+
+// FitGEV fits the GEV distribution to data. Maximum likelihood estimates are
+// found by the Nelder-Mead method, starting from the L-moment estimates.
+func FitGEV(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitGEV", data, 3, math.Inf(-1)); err != nil {
+		return nil, err
+	}
+	var d Distribution
+	var err error
+	switch method {
+	case MethodOfMoments:
+		d, err = NewGEVMoments(SampleMoments(data))
+	case MaximumLikelihood:
+		l1, l2, t3, _ := SampleLMoments(data)
+		var g *GEV
+		if g, err = NewGEVLMoments(l1, l2, t3); err != nil {
+			return nil, err
+		}
+		d, err = mle("FitGEV", func(p []float64) (Distribution, error) {
+			return NewGEV(p[0], math.Exp(p[1]), p[2])
+		}, data, []float64{g.mu, math.Log(g.sigma), g.xi}, []float64{.1 * g.sigma, .1, .05})
+	case LMoments:
+		l1, l2, t3, _ := SampleLMoments(data)
+		d, err = NewGEVLMoments(l1, l2, t3)
+	default:
+		return nil, unsupported("FitGEV", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 3, data), nil
+}
+
+// fitPearson3 returns the Pearson type III distribution fit to data
+func fitPearson3(fn string, data []float64, method FitMethod) (*Pearson3, error) {
+	switch method {
+	case MethodOfMoments:
+		return NewPearson3Moments(SampleMoments(data))
+	case MaximumLikelihood:
+		m, s, g := SampleMoments(data)
+		if _, err := NewPearson3Moments(m, s, g); err != nil {
+			return nil, err
+		}
+		d, err := mle(fn, func(p []float64) (Distribution, error) {
+			return NewPearson3Moments(p[0], math.Exp(p[1]), p[2])
+		}, data, []float64{m, math.Log(s), g}, []float64{.1 * s, .1, .1})
+		if err != nil {
+			return nil, err
+		}
+		return d.(*Pearson3), nil
+	case LMoments:
+		l1, l2, t3, _ := SampleLMoments(data)
+		return NewPearson3LMoments(l1, l2, t3)
+	}
+	return nil, unsupported(fn, method)
+}
+
+// FitPearson3 fits the Pearson type III distribution to data. Maximum likelihood estimates are
+// found by the Nelder-Mead method, starting from the moment estimates; note that the likelihood
+// is unbounded for shape < 1 (|skew| > 2).
+func FitPearson3(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitPearson3", data, 3, math.Inf(-1)); err != nil {
+		return nil, err
+	}
+	d, err := fitPearson3("FitPearson3", data, method)
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 3, data), nil
+}
+
+// FitLogPearson3 fits the log-Pearson type III distribution to (positive) data,
+// estimating the parameters from log10(data) (Bulletin 17B/C)
+func FitLogPearson3(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitLogPearson3", data, 3, 0.); err != nil {
+		return nil, err
+	}
+	p3, err := fitPearson3("FitLogPearson3", transform(data, math.Log10), method)
+	if err != nil {
+		return nil, err
+	}
+	return newFit(&LogPearson3{p3}, 0., 1., method, 3, data), nil
+}
+
+// FitBeta fits the Beta distribution to data bounded by (low, high). Maximum likelihood
+// estimates are found by the Nelder-Mead method, starting from the moment estimates.
+func FitBeta(data []float64, low, high float64, method FitMethod) (*Fit, error) {
+	if low >= high {
+		return nil, fmt.Errorf("invdistr.FitBeta: invalid bounds low, high = %v, %v: %w", low, high, ErrInvalidBounds)
+	}
+	x := transform(data, func(v float64) float64 { return (v - low) / (high - low) })
+	if err := checkData("FitBeta", x, 2, 0.); err != nil {
+		return nil, err
+	}
+	for i, v := range x {
+		if v >= 1. {
+			return nil, fmt.Errorf("invdistr.FitBeta: datum [%d] = %v beyond upper bound %v: %w", i, data[i], high, ErrInvalidBounds)
+		}
+	}
+	m, s, _ := SampleMoments(x)
+	c := m*(1.-m)/s/s - 1.
+	if c <= 0. {
+		return nil, fmt.Errorf("invdistr.FitBeta: sample variance too large for a Beta distribution")
+	}
+	var d Distribution
+	var err error
+	switch method {
+	case MethodOfMoments:
+		d, err = NewBeta(m*c, (1.-m)*c)
+	case MaximumLikelihood:
+		d, err = mle("FitBeta", func(p []float64) (Distribution, error) {
+			return NewBeta(math.Exp(p[0]), math.Exp(p[1]))
+		}, x, []float64{math.Log(m * c), math.Log((1. - m) * c)}, []float64{.1, .1})
+	default:
+		return nil, unsupported("FitBeta", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, low, high, method, 2, data), nil
+}
+
+// FitJohnson fits the Johnson system to data by moments (Hill et.al., 1976) or by
+// matching the sample quantiles at Φ(±0.524) and Φ(±1.572) (Slifker and Shapiro, 1980).
+func FitJohnson(data []float64, method FitMethod) (*Fit, error) {
+	if err := checkData("FitJohnson", data, 4, math.Inf(-1)); err != nil {
+		return nil, err
+	}
+	var d *Johnson
+	var err error
+	switch method {
+	case MethodOfMoments:
+		m, s, g := SampleMoments(data)
+		m2, m4 := 0., 0.
+		for _, v := range data {
+			dv := (v - m) * (v - m)
+			m2 += dv
+			m4 += dv * dv
+		}
+		n := float64(len(data))
+		d, err = NewJohnsonMoments(m, s, g, n*m4/m2/m2)
+	case QuantileMatching:
+		const z = .524
+		var e *PiecewiseLinear
+		if e, err = NewEmpirical(data); err != nil {
+			return nil, err
+		}
+		d, err = NewJohnsonQuantiles(z, e.Inv(normCDF(-3.*z)), e.Inv(normCDF(-z)), e.Inv(normCDF(z)), e.Inv(normCDF(3.*z)))
+	default:
+		return nil, unsupported("FitJohnson", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newFit(d, 0., 1., method, 4, data), nil
+}
+
+// checkQuantiles returns an error if probabilities p and quantiles x are not
+// of equal length (at least nmin) and increasing
+func checkQuantiles(fn string, p, x []float64, nmin int) error {
+	if len(p) != len(x) || len(p) < nmin {
+		return fmt.Errorf("invdistr.%s: at least %d probabilities and quantiles of equal length required, %d and %d given: %w", fn, nmin, len(p), len(x), ErrDimensionMismatch)
+	}
+	for i := range p {
+		if p[i] <= 0. || p[i] >= 1. || (i > 0 && (p[i] <= p[i-1] || x[i] < x[i-1])) {
+			return fmt.Errorf("invdistr.%s: invalid (or non-increasing) quantile [%d] p, x = %v, %v", fn, i, p[i], x[i])
+		}
+	}
+	return nil
+}
+
+// quantileRMSE returns the root-mean-squared difference between the quantiles of m and x at probabilities p
+func quantileRMSE(m *Map, p, x []float64) float64 {
+	s := 0.
+	for i := range p {
+		d := m.P(p[i]) - x[i]
+		s += d * d
+	}
+	return math.Sqrt(s / float64(len(p)))
+}
+
+// FitTriangleQuantiles fits the Triangular distribution with quantiles x at probabilities p,
+// e.g., from P10/P50/P90 expert estimates: FitTriangleQuantiles([]float64{.1, .5, .9}, []float64{p10, p50, p90}).
+// The bounds and mode are found by least squares using the Nelder-Mead method.
+func FitTriangleQuantiles(p, x []float64) (*Fit, error) {
+	if err := checkQuantiles("FitTriangleQuantiles", p, x, 3); err != nil {
+		return nil, err
+	}
+	n := len(x)
+	r := x[n-1] - x[0]
+	if r <= 0. {
+		return nil, fmt.Errorf("invdistr.FitTriangleQuantiles: quantiles have no spread")
+	}
+	triangle := func(t []float64) *Map { // t: low, ln(range), mode
+		return &Map{Low: t[0], High: t[0] + math.Exp(t[1]), Distr: NewTriangle(math.Min(math.Max(t[2], 0.), 1.))}
+	}
+	t0 := []float64{x[0] - r/2., math.Log(2. * r), 0.}
+	t0[2] = (x[n/2] - t0[0]) / 2. / r
+	t := nelderMead(func(t []float64) float64 {
+		e := quantileRMSE(triangle(t), p, x) / r
+		return e * e
+	}, t0, []float64{.1 * r, .1, .1})
+	m := triangle(t)
+	return &Fit{Map: m, Method: QuantileMatching, N: n, K: 3, QuantileRMSE: quantileRMSE(m, p, x)}, nil
+}
+
+// FitJohnsonBQuantiles fits the JohnsonB distribution bounded by (low, high) with quantiles x
+// at probabilities p, e.g., from P10/P50/P90 expert estimates. Shape parameters are found by
+// linear regression of the standard normal deviate of p on logit((x-low)/(high-low)).
+func FitJohnsonBQuantiles(low, high float64, p, x []float64) (*Fit, error) {
+	if err := checkQuantiles("FitJohnsonBQuantiles", p, x, 2); err != nil {
+		return nil, err
+	}
+	if low >= high || x[0] <= low || x[len(x)-1] >= high {
+		return nil, fmt.Errorf("invdistr.FitJohnsonBQuantiles: quantiles must lie within bounds low, high = %v, %v: %w", low, high, ErrInvalidBounds)
+	}
+	n := float64(len(p))
+	y, z := make([]float64, len(p)), make([]float64, len(p))
+	my, mz := 0., 0.
+	for i := range p {
+		r := (x[i] - low) / (high - low)
+		y[i], z[i] = math.Log(r/(1.-r)), normQuantile(p[i])
+		my += y[i] / n
+		mz += z[i] / n
+	}
+	sxy, sxx := 0., 0.
+	for i := range y {
+		sxy += (y[i] - my) * (z[i] - mz)
+		sxx += (y[i] - my) * (y[i] - my)
+	}
+	if sxx <= 0. {
+		return nil, fmt.Errorf("invdistr.FitJohnsonBQuantiles: quantiles have no spread")
+	}
+	a2 := sxy / sxx
+	d, err := NewJohnsonBShape(mz-a2*my, a2)
+	if err != nil {
+		return nil, err
+	}
+	m := &Map{Low: low, High: high, Distr: d}
+	return &Fit{Map: m, Method: QuantileMatching, N: len(p), K: 2, QuantileRMSE: quantileRMSE(m, p, x)}, nil
+}
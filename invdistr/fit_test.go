@@ -0,0 +1,169 @@
+package invdistr
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// fitParams returns the parameters of the distributions fitted in fit.go
+func fitParams(d Distribution) []float64 {
+	switch t := d.(type) {
+	case *Normal:
+		return []float64{t.mu, t.sigma}
+	case *LogNormal:
+		return []float64{t.mu, t.sigma}
+	case *Gamma:
+		return []float64{t.k, t.theta}
+	case *Weibull:
+		return []float64{t.k, t.lambda}
+	case *Gumbel:
+		return []float64{t.mu, t.beta}
+	case *GEV:
+		return []float64{t.mu, t.sigma, t.xi}
+	case *Pearson3:
+		return []float64{t.mean, t.sd, t.skew}
+	case *LogPearson3:
+		return []float64{t.p3.mean, t.p3.sd, t.p3.skew}
+	case *Beta:
+		return []float64{t.a, t.b}
+	case *Johnson:
+		return []float64{t.gamma, t.delta, t.xi, t.lambda}
+	}
+	return nil
+}
+
+// draw returns n seeded samples of distribution d
+func draw(d Mapper, n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = d.Inv(rng.Float64())
+	}
+	return x
+}
+
+// TestFit checks that every fitter recovers the parameters of the distribution sampled, by every method
+func TestFit(t *testing.T) {
+	mk := func(d Distribution, err error) Distribution {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	beta := func(data []float64, method FitMethod) (*Fit, error) { return FitBeta(data, 0., 1., method) }
+	mm, ml, lm := MethodOfMoments, MaximumLikelihood, LMoments
+	for _, c := range []struct {
+		name    string
+		d       Distribution
+		fit     func([]float64, FitMethod) (*Fit, error)
+		methods []FitMethod
+		tol     float64 // relative to each parameter, absolute below one (quantiles only when zero)
+	}{
+		{"normal", mk(NewNormal(10., 2.)), FitNormal, []FitMethod{mm, ml, lm}, .05},
+		{"lognormal", mk(NewLogNormal(1., .5)), FitLogNormal, []FitMethod{mm, ml, lm}, .05},
+		{"gamma", mk(NewGamma(2.5, 3.)), FitGamma, []FitMethod{mm, ml, lm}, .1},
+		{"weibull", mk(NewWeibull(1.8, 4.)), FitWeibull, []FitMethod{mm, ml, lm}, .05},
+		{"gumbel", mk(NewGumbel(20., 5.)), FitGumbel, []FitMethod{mm, ml, lm}, .05},
+		{"gev", mk(NewGEV(20., 5., .1)), FitGEV, []FitMethod{ml, lm}, .1},
+		{"pearson3", mk(NewPearson3Moments(50., 10., .8)), FitPearson3, []FitMethod{mm, ml, lm}, .15},
+		{"log-pearson3", &LogPearson3{mk(NewPearson3Moments(1., .2, .3)).(*Pearson3)}, FitLogPearson3, []FitMethod{mm, ml, lm}, .15},
+		{"beta", mk(NewBeta(2., 5.)), beta, []FitMethod{mm, ml}, .1},
+		{"johnson su", mk(NewJohnsonSU(.5, 2., 1., 3.)), FitJohnson, []FitMethod{mm, QuantileMatching}, 0.}, // parameters poorly identified
+	} {
+		data := draw(c.d, 5000, 1)
+		want := fitParams(c.d)
+		for _, method := range c.methods {
+			f, err := c.fit(data, method)
+			if err != nil {
+				t.Errorf("%s %v: %v", c.name, method, err)
+				continue
+			}
+			got := fitParams(f.Distribution())
+			for i := range want {
+				if c.tol > 0. && math.Abs(got[i]-want[i]) > c.tol*math.Max(math.Abs(want[i]), 1.) {
+					t.Errorf("%s %v: parameters %v, want %v", c.name, method, got, want)
+					break
+				}
+			}
+			for _, u := range []float64{.05, .25, .5, .75, .95} {
+				if x, e := f.Map.P(u), c.d.Inv(u); math.Abs(x-e) > .1*math.Sqrt(c.d.Variance()) {
+					t.Errorf("%s %v: quantile %v = %v, want %v", c.name, method, u, x, e)
+				}
+			}
+			if f.KS > .03 || f.N != len(data) || math.IsNaN(f.AIC) || math.IsNaN(f.AD) {
+				t.Errorf("%s %v: N, KS, AIC, AD = %d, %v, %v, %v", c.name, method, f.N, f.KS, f.AIC, f.AD)
+			}
+		}
+	}
+}
+
+// TestFitQuantiles checks that the quantile-matching fitters recover the distribution whose quantiles are given
+func TestFitQuantiles(t *testing.T) {
+	p := []float64{.1, .5, .9}
+	tri := &Map{Low: 2., High: 8., Distr: NewTriangle(.3)}
+	x := []float64{tri.P(.1), tri.P(.5), tri.P(.9)}
+	f, err := FitTriangleQuantiles(p, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.QuantileRMSE > 1e-4 || math.Abs(f.Map.Low-2.) > 1e-2 || math.Abs(f.Map.High-8.) > 1e-2 {
+		t.Errorf("FitTriangleQuantiles: range %v, %v, RMSE %v", f.Map.Low, f.Map.High, f.QuantileRMSE)
+	}
+
+	jb, err := NewJohnsonBShape(-.4, 1.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Map{Low: 1., High: 3., Distr: jb}
+	x = []float64{m.P(.1), m.P(.5), m.P(.9)}
+	if f, err = FitJohnsonBQuantiles(1., 3., p, x); err != nil {
+		t.Fatal(err)
+	}
+	if a1, a2 := f.Map.Distr.(*JohnsonB).Shape(); math.Abs(a1+.4) > 1e-9 || math.Abs(a2-1.3) > 1e-9 {
+		t.Errorf("FitJohnsonBQuantiles: alpha1, alpha2 = %v, %v", a1, a2)
+	}
+}
+
+// TestFitErrors checks the errors returned for too few data, data outside the support and unsupported methods
+func TestFitErrors(t *testing.T) {
+	for name, fit := range map[string]func([]float64, FitMethod) (*Fit, error){
+		"normal": FitNormal, "lognormal": FitLogNormal, "gamma": FitGamma, "weibull": FitWeibull,
+		"gumbel": FitGumbel, "gev": FitGEV, "pearson3": FitPearson3, "log-pearson3": FitLogPearson3, "johnson": FitJohnson,
+	} {
+		if _, err := fit([]float64{1.}, MethodOfMoments); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("%s of one datum: error = %v", name, err)
+		}
+		if _, err := fit([]float64{1., 2., math.NaN(), 4.}, MethodOfMoments); err == nil {
+			t.Errorf("%s of NaN data accepted", name)
+		}
+		if _, err := fit([]float64{1., 2., 3., 4., 5.}, FitMethod(9)); err == nil {
+			t.Errorf("%s: unknown method accepted", name)
+		}
+	}
+	for name, fit := range map[string]func([]float64, FitMethod) (*Fit, error){
+		"lognormal": FitLogNormal, "gamma": FitGamma, "weibull": FitWeibull, "log-pearson3": FitLogPearson3,
+	} {
+		for _, v := range []float64{0., -1.} {
+			if _, err := fit([]float64{1., 2., v, 4.}, MaximumLikelihood); err == nil {
+				t.Errorf("%s of datum %v accepted", name, v)
+			}
+		}
+	}
+	if _, err := FitBeta([]float64{.2, .5, 1.2}, 0., 1., MethodOfMoments); !errors.Is(err, ErrInvalidBounds) {
+		t.Errorf("FitBeta of data beyond its bounds: error = %v", err)
+	}
+	if _, err := FitBeta([]float64{.2, .5}, 1., 0., MethodOfMoments); !errors.Is(err, ErrInvalidBounds) {
+		t.Errorf("FitBeta of inverted bounds: error = %v", err)
+	}
+	if _, err := FitTriangleQuantiles([]float64{.1, .9}, []float64{1., 2.}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("FitTriangleQuantiles of two quantiles: error = %v", err)
+	}
+	if _, err := FitTriangleQuantiles([]float64{.1, .5, .9}, []float64{1., 3., 2.}); err == nil {
+		t.Error("FitTriangleQuantiles of decreasing quantiles accepted")
+	}
+	if _, err := FitJohnsonBQuantiles(0., 1., []float64{.1, .9}, []float64{.2, 1.5}); !errors.Is(err, ErrInvalidBounds) {
+		t.Errorf("FitJohnsonBQuantiles beyond its bounds: error = %v", err)
+	}
+}
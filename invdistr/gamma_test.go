@@ -0,0 +1,76 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// TestGammaChiSquareTable checks Gamma quantiles against tabulated percentage points of the
+// chi-square distribution, where chi-square with nu degrees of freedom is Gamma(nu/2, 2)
+// see Table 26.8 of: Abramowitz, M. and I.A. Stegun, 1972. Handbook of Mathematical Functions. Dover, New York. 1046pp.
+func TestGammaChiSquareTable(t *testing.T) {
+	for _, c := range []struct {
+		nu, p, x float64
+	}{
+		{1., .01, .000157088},
+		{1., .05, .00393214},
+		{1., .95, 3.841459},
+		{1., .99, 6.634897},
+		{2., .05, .1025866},
+		{2., .95, 5.991465},
+		{5., .05, 1.145476},
+		{5., .95, 11.070498},
+		{5., .99, 15.086272},
+		{10., .05, 3.940299},
+		{10., .95, 18.307038},
+		{10., .99, 23.209251},
+	} {
+		g, err := NewGamma(c.nu/2., 2.)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x := g.Inv(c.p); relErr(x, c.x) > 1e-5 {
+			t.Errorf("chi-square(%v).Inv(%v) = %v, want %v", c.nu, c.p, x, c.x)
+		}
+		if p := g.CDF(c.x); relErr(p, c.p) > 1e-5 {
+			t.Errorf("chi-square(%v).CDF(%v) = %v, want %v", c.nu, c.x, p, c.p)
+		}
+	}
+}
+
+// TestGammaClosedForm checks Gamma quantiles of small shape parameters and extreme u against
+// closed forms: Gamma(1) = -ln(1-u), Gamma(.5) = erfinv(u)², and Gamma(a) ≈ (uaΓ(a))^(1/a) as u → 0
+func TestGammaClosedForm(t *testing.T) {
+	for _, c := range []struct {
+		k   float64
+		inv func(u float64) float64
+	}{
+		{1., func(u float64) float64 { return -math.Log1p(-u) }},
+		{.5, func(u float64) float64 { e := math.Erfinv(u); return e * e }},
+	} {
+		g, err := NewGamma(c.k, 1.)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []float64{1e-10, 1e-4, .05, .5, .95, 1. - 1e-4, 1. - 1e-10} {
+			want := c.inv(u)
+			if x := g.Inv(u); relErr(x, want) > 1e-8 {
+				t.Errorf("Gamma(%v).Inv(%v) = %v, want %v", c.k, u, x, want)
+			}
+			if p := g.CDF(want); relErr(p, u) > 1e-8 {
+				t.Errorf("Gamma(%v).CDF(%v) = %v, want %v", c.k, want, p, u)
+			}
+		}
+	}
+	for _, k := range []float64{.1, .3} {
+		g, err := NewGamma(k, 1.)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u := 1e-10
+		want := math.Pow(u*k*math.Gamma(k), 1./k)
+		if x := g.Inv(u); relErr(x, want) > 1e-6 {
+			t.Errorf("Gamma(%v).Inv(%v) = %v, want %v", k, u, x, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// gev.go returns a Generalized Extreme Value probability distribution from u[0,1]
+// with location mu, scale sigma and shape xi, where F(x) = exp(-(1+xi(x-mu)/sigma)^(-1/xi)).
+// Note: the shape parameter of Hosking and Wallis (1997) is k = -xi; xi > 0 is heavy-tailed (Fréchet).
+// GEV returns values in parameter space (use with a Map where Low=0, High=1).
+// see Coles, S., 2001. An Introduction to Statistical Modeling of Extreme Values. Springer-Verlag. 208pp.
+
+package invdistr
+
+import (
+	"fmt"
+	"math"
+)
+
+// GEV sampling distribution
+type GEV struct {
+	mu, sigma, xi float64 // location, scale and shape
+}
+
+// NewGEV constructor
+func NewGEV(mu, sigma, xi float64) (*GEV, error) {
+	if sigma <= 0. || math.IsInf(mu, 0) || math.IsNaN(mu) || math.IsNaN(xi) || math.IsInf(xi, 0) {
+		return nil, fmt.Errorf("invdistr.NewGEV: invalid arguments mu, sigma, xi = %v, %v, %v: %w", mu, sigma, xi, ErrInvalidShape)
+	}
+	return &GEV{mu: mu, sigma: sigma, xi: xi}, nil
+}
+
+// NewGEVMoments constructor from the mean, standard deviation and skewness (skew > -2).
+// The shape parameter is solved numerically; moments exist only for xi < 1/3.
+func NewGEVMoments(mean, sd, skew float64) (*GEV, error) {
+	if skew <= -2. || sd <= 0. {
+		return nil, fmt.Errorf("invdistr.NewGEVMoments: invalid arguments mean, sd, skew = %v, %v, %v: %w", mean, sd, skew, ErrInvalidShape)
+	}
+	xi := bisect(func(xi float64) float64 { return gevSkew(xi) - skew }, -20., 1./3.-1e-9)
+	if math.Abs(xi) < 1e-6 {
+		b := sd * math.Sqrt(6.) / math.Pi
+		return NewGEV(mean-eulerGamma*b, b, 0.)
+	}
+	g1, g2 := math.Gamma(1.-xi), math.Gamma(1.-2.*xi)
+	s := sd * math.Abs(xi) / math.Sqrt(g2-g1*g1)
+	return NewGEV(mean-s*(g1-1.)/xi, s, xi)
+}
+
+// gevSkew returns the skewness of the GEV with shape xi
+func gevSkew(xi float64) float64 {
+	if math.Abs(xi) < 1e-6 {
+		return 12. * math.Sqrt(6.) * 1.2020569031595942 / math.Pow(math.Pi, 3.) // Gumbel: 12√6ζ(3)/π³
+	}
+	g1, g2, g3 := math.Gamma(1.-xi), math.Gamma(1.-2.*xi), math.Gamma(1.-3.*xi)
+	s := (g3 - 3.*g1*g2 + 2.*g1*g1*g1) / math.Pow(g2-g1*g1, 1.5)
+	if xi < 0. {
+		return -s
+	}
+	return s
+}
+
+// NewGEVLMoments constructor from L-moments l1, l2 and L-skewness t3
+// using the approximation of Hosking et.al. (1985), valid for -0.5 < t3 < 0.5
+func NewGEVLMoments(l1, l2, t3 float64) (*GEV, error) {
+	if l2 <= 0. || t3 <= -1. || t3 >= 1. {
+		return nil, fmt.Errorf("invdistr.NewGEVLMoments: invalid arguments l1, l2, t3 = %v, %v, %v: %w", l1, l2, t3, ErrInvalidShape)
+	}
+	c := 2./(3.+t3) - math.Ln2/math.Log(3.)
+	k := 7.8590*c + 2.9554*c*c
+	if math.Abs(k) < 1e-9 {
+		return NewGEV(l1-eulerGamma*l2/math.Ln2, l2/math.Ln2, 0.)
+	}
+	g := math.Gamma(1. + k)
+	a := l2 * k / (1. - math.Pow(2., -k)) / g
+	return NewGEV(l1-a*(1.-g)/k, a, -k)
+}
+
+// Inv : inverse function
+func (t *GEV) Inv(u float64) float64 {
+	if t.xi == 0. {
+		return t.mu - t.sigma*math.Log(-math.Log(u))
+	}
+	return t.mu + t.sigma*(math.Pow(-math.Log(u), -t.xi)-1.)/t.xi
+}
+
+// tz returns t(x) where F(x) = exp(-t(x)), and false if x is beyond the support
+func (t *GEV) tz(x float64) (float64, bool) {
+	z := (x - t.mu) / t.sigma
+	if t.xi == 0. {
+		return math.Exp(-z), true
+	}
+	b := 1. + t.xi*z
+	if b <= 0. {
+		return 0., false
+	}
+	return math.Pow(b, -1./t.xi), true
+}
+
+// PDF : probability density function
+func (t *GEV) PDF(x float64) float64 {
+	tx, ok := t.tz(x)
+	if !ok {
+		return 0.
+	}
+	return math.Pow(tx, t.xi+1.) * math.Exp(-tx) / t.sigma
+}
+
+// CDF : cumulative distribution function
+func (t *GEV) CDF(x float64) float64 {
+	tx, ok := t.tz(x)
+	if !ok {
+		if t.xi > 0. {
+			return 0.
+		}
+		return 1.
+	}
+	return math.Exp(-tx)
+}
+
+// Mean of the distribution (infinite for xi >= 1)
+func (t *GEV) Mean() float64 {
+	switch {
+	case t.xi == 0.:
+		return t.mu + eulerGamma*t.sigma
+	case t.xi >= 1.:
+		return math.Inf(1)
+	}
+	return t.mu + t.sigma*(math.Gamma(1.-t.xi)-1.)/t.xi
+}
+
+// Variance of the distribution (infinite for xi >= 1/2)
+func (t *GEV) Variance() float64 {
+	switch {
+	case t.xi == 0.:
+		return math.Pi * math.Pi * t.sigma * t.sigma / 6.
+	case t.xi >= .5:
+		return math.Inf(1)
+	}
+	g1, g2 := math.Gamma(1.-t.xi), math.Gamma(1.-2.*t.xi)
+	return t.sigma * t.sigma * (g2 - g1*g1) / t.xi / t.xi
+}
+
+// Support returns the range of the distribution
+func (t *GEV) Support() (float64, float64) {
+	switch {
+	case t.xi > 0.:
+		return t.mu - t.sigma/t.xi, math.Inf(1)
+	case t.xi < 0.:
+		return math.Inf(-1), t.mu - t.sigma/t.xi
+	}
+	return math.Inf(-1), math.Inf(1)
+}
@@ -0,0 +1,64 @@
+// gumbel.go returns a Gumbel (extreme value type I) probability distribution
+// from u[0,1] with location mu and scale beta.
+// Gumbel is unbounded and returns values in parameter space (use with a Map where Low=0, High=1).
+// see Hosking, J.R.M. and J.R. Wallis, 1997. Regional Frequency Analysis: An Approach Based on L-Moments. Cambridge University Press. 224pp.
+
+package invdistr
+
+import (
+	"fmt"
+	"math"
+)
+
+// eulerGamma is the Euler-Mascheroni constant
+const eulerGamma = 0.5772156649015329
+
+// Gumbel sampling distribution
+type Gumbel struct {
+	mu, beta float64 // location and scale
+}
+
+// NewGumbel constructor
+func NewGumbel(mu, beta float64) (*Gumbel, error) {
+	if beta <= 0. || math.IsInf(mu, 0) || math.IsNaN(mu) {
+		return nil, fmt.Errorf("invdistr.NewGumbel: invalid arguments mu, beta = %v, %v: %w", mu, beta, ErrInvalidShape)
+	}
+	return &Gumbel{mu: mu, beta: beta}, nil
+}
+
+// NewGumbelMoments constructor from the mean and standard deviation
+func NewGumbelMoments(mean, sd float64) (*Gumbel, error) {
+	b := sd * math.Sqrt(6.) / math.Pi
+	return NewGumbel(mean-eulerGamma*b, b)
+}
+
+// NewGumbelLMoments constructor from L-moments l1 and l2
+func NewGumbelLMoments(l1, l2 float64) (*Gumbel, error) {
+	b := l2 / math.Ln2
+	return NewGumbel(l1-eulerGamma*b, b)
+}
+
+// Inv : inverse function
+func (t *Gumbel) Inv(u float64) float64 {
+	return t.mu - t.beta*math.Log(-math.Log(u))
+}
+
+// PDF : probability density function
+func (t *Gumbel) PDF(x float64) float64 {
+	z := (x - t.mu) / t.beta
+	return math.Exp(-z-math.Exp(-z)) / t.beta
+}
+
+// CDF : cumulative distribution function
+func (t *Gumbel) CDF(x float64) float64 {
+	return math.Exp(-math.Exp(-(x - t.mu) / t.beta))
+}
+
+// Mean of the distribution
+func (t *Gumbel) Mean() float64 { return t.mu + eulerGamma*t.beta }
+
+// Variance of the distribution
+func (t *Gumbel) Variance() float64 { return math.Pi * math.Pi * t.beta * t.beta / 6. }
+
+// Support returns the range of the distribution
+func (t *Gumbel) Support() (float64, float64) { return math.Inf(-1), math.Inf(1) }
@@ -7,6 +7,46 @@ type Mapper interface {
 	Inv(u float64) float64
 }
 
+// Distribution : interface to MC distribution transforms having a known
+// probability density, cumulative distribution and moments. Distributions
+// defined on [0,1] (i.e., Uniform, JohnsonB, Trapezoid, Beta) are evaluated
+// relative to the Map range, others in parameter space.
+type Distribution interface {
+	Mapper
+	PDF(x float64) float64
+	CDF(x float64) float64
+	Mean() float64
+	Variance() float64
+	Support() (float64, float64)
+}
+
+var (
+	_ Distribution = (*Uniform)(nil)
+	_ Distribution = (*JohnsonB)(nil)
+	_ Distribution = (*Trapezoid)(nil)
+	_ Distribution = (*Triangle)(nil)
+	_ Distribution = (*Normal)(nil)
+	_ Distribution = (*StudentT)(nil)
+	_ Distribution = (*TruncatedNormal)(nil)
+	_ Distribution = (*LogNormal)(nil)
+	_ Distribution = (*TruncatedLogNormal)(nil)
+	_ Distribution = (*Beta)(nil)
+	_ Distribution = (*Gamma)(nil)
+	_ Distribution = (*Weibull)(nil)
+	_ Distribution = (*Gumbel)(nil)
+	_ Distribution = (*GEV)(nil)
+	_ Distribution = (*Pearson3)(nil)
+	_ Distribution = (*LogPearson3)(nil)
+	_ Distribution = (*PiecewiseLinear)(nil)
+	_ Distribution = (*KernelDensity)(nil)
+	_ Distribution = (*Johnson)(nil)
+	_ Distribution = (*DiscreteUniform)(nil)
+	_ Distribution = (*Poisson)(nil)
+	_ Distribution = (*Binomial)(nil)
+	_ Distribution = (*Categorical)(nil)
+	_ Distribution = (*Mixture)(nil)
+)
+
 // Map is a type used to contain sample mapping info
 type Map struct {
 	Low   float64
@@ -24,8 +64,96 @@ func (m *Map) P(u float64) float64 {
 	return p
 }
 
+// CDF returns the cumulative probability of parameter value x, the inverse of P.
+// When the distribution has no CDF, the inverse is solved by bisection.
+func (m *Map) CDF(x float64) float64 {
+	if m.Log {
+		if x <= 0. {
+			return 0.
+		}
+		x = math.Log10(x)
+	}
+	if m.High == m.Low { // constant
+		if x < m.Low {
+			return 0.
+		}
+		return 1.
+	}
+	r := (x - m.Low) / (m.High - m.Low)
+	if d, ok := m.Distr.(Distribution); ok {
+		return d.CDF(r)
+	}
+	if r <= m.Distr.Inv(0.) {
+		return 0.
+	} else if r >= m.Distr.Inv(1.) {
+		return 1.
+	}
+	return bisect(func(u float64) float64 { return m.Distr.Inv(u) - r }, 0., 1.)
+}
+
+// PDF returns the probability density (or mass for discrete distributions) of parameter value x.
+// When the distribution has no PDF, the density is approximated numerically from the CDF.
+func (m *Map) PDF(x float64) float64 {
+	if m.High == m.Low { // constant
+		if x == m.P(0.) {
+			return math.Inf(1)
+		}
+		return 0.
+	}
+	d, ok := m.Distr.(Distribution)
+	if !ok {
+		h := 1e-6 * math.Max(math.Abs(x), 1e-3*math.Abs(m.P(1.)-m.P(0.)))
+		return (m.CDF(x+h) - m.CDF(x-h)) / 2. / h
+	}
+	y, j := x, 1./(m.High-m.Low)
+	if m.Log {
+		if x <= 0. {
+			return 0.
+		}
+		y = math.Log10(x)
+		j /= x * math.Ln10
+	}
+	if isDiscrete(d) {
+		j = 1.
+	}
+	return d.PDF((y-m.Low)/(m.High-m.Low)) * j
+}
+
+// Unsample returns the u[0,1] sample that maps to parameter value x, such that P(Unsample(x)) = x.
+// For discrete distributions, u is the midpoint of the interval mapping to x.
+func (m *Map) Unsample(x float64) float64 {
+	if m.High == m.Low {
+		return .5
+	}
+	u := m.CDF(x)
+	if d, ok := m.Distr.(Distribution); ok && isDiscrete(d) {
+		u -= m.PDF(x) / 2.
+	}
+	return u
+}
+
 // Uniform sampling distribution
 type Uniform struct{}
 
 // Inv : inverse function
 func (t *Uniform) Inv(u float64) float64 { return u }
+
+// PDF : probability density function
+func (t *Uniform) PDF(x float64) float64 {
+	if x < 0. || x > 1. {
+		return 0.
+	}
+	return 1.
+}
+
+// CDF : cumulative distribution function
+func (t *Uniform) CDF(x float64) float64 { return math.Min(math.Max(x, 0.), 1.) }
+
+// Mean of the distribution
+func (t *Uniform) Mean() float64 { return .5 }
+
+// Variance of the distribution
+func (t *Uniform) Variance() float64 { return 1. / 12. }
+
+// Support returns the range of the distribution
+func (t *Uniform) Support() (float64, float64) { return 0., 1. }
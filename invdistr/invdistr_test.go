@@ -0,0 +1,113 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMapUnsample checks that Unsample inverts P, Unsample(P(u)) = u, for bounded,
+// unbounded, log-mapped and mixture distributions
+func TestMapUnsample(t *testing.T) {
+	mk := func(d Mapper, err error) Mapper {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	tn := mk(NewTruncatedNormal(.4, .2, 0., 1.)).(*TruncatedNormal)
+	jb := mk(NewJohnsonBPeak(.3, 3.)).(*JohnsonB)
+	nm := mk(NewNormal(0., 1.)).(*Normal)
+	ln := mk(NewLogNormal(0., .5)).(*LogNormal)
+	maps := []struct {
+		name string
+		m    *Map
+	}{
+		{"uniform", &Map{Low: -1., High: 3., Distr: &Uniform{}}},
+		{"johnsonb", &Map{Low: 2., High: 5., Distr: jb}},
+		{"trapezoid", &Map{Low: 0., High: 10., Distr: mk(NewTrapezoidE(.2, .6, 2., 3.))}},
+		{"triangle", &Map{Low: 0., High: 1., Distr: &Triangle{m: .7}}},
+		{"beta", &Map{Low: 1., High: 2., Distr: mk(NewBeta(2., 5.))}},
+		{"pert", &Map{Low: 0., High: 1., Distr: mk(NewPERT(0., .2, 1.))}},
+		{"truncated normal", &Map{Low: 0., High: 1., Distr: tn}},
+		{"normal", &Map{Low: 0., High: 1., Distr: nm}},
+		{"student-t", &Map{Low: 0., High: 1., Distr: mk(NewStudentT(4., 1., 2.))}},
+		{"gumbel", &Map{Low: 0., High: 1., Distr: mk(NewGumbel(10., 3.))}},
+		{"gev", &Map{Low: 0., High: 1., Distr: mk(NewGEV(10., 3., .1))}},
+		{"gamma", &Map{Low: 0., High: 1., Distr: mk(NewGamma(2., 3.))}},
+		{"weibull", &Map{Low: 0., High: 1., Distr: mk(NewWeibull(1.5, 2.))}},
+		{"pearson3", &Map{Low: 0., High: 1., Distr: mk(NewPearson3(1., 2., .5))}},
+		{"johnson su", &Map{Low: 0., High: 1., Distr: mk(NewJohnsonSU(.5, 1.5, 0., 1.))}},
+		{"lognormal", &Map{Low: 0., High: 1., Distr: ln}},
+		{"piecewise", &Map{Low: 0., High: 1., Distr: mk(NewPiecewiseLinear([]float64{0., 1., 3.}, []float64{0., .7, 1.}))}},
+		{"log beta", &Map{Low: -2., High: 1., Log: true, Distr: mk(NewBeta(2., 2.))}},
+		{"log johnsonb", &Map{Low: -3., High: 0., Log: true, Distr: jb}},
+		{"log normal", &Map{Low: 0., High: 1., Log: true, Distr: nm}},
+		{"mixture", &Map{Low: 0., High: 1., Distr: mk(NewMixture([]float64{.3, .7}, nm, ln))}},
+		{"bounded mixture", &Map{Low: 0., High: 4., Distr: mk(NewMixture([]float64{1., 2.}, jb, tn))}},
+	}
+	for _, c := range maps {
+		for _, u := range []float64{.001, .05, .25, .5, .75, .95, .999} {
+			x := c.m.P(u)
+			if got := c.m.Unsample(x); math.Abs(got-u) > 1e-7 {
+				t.Errorf("%s: Unsample(P(%v) = %v) = %v", c.name, u, x, got)
+			}
+		}
+	}
+}
+
+// TestMapUnsampleDiscrete checks that P(Unsample(x)) = x over the support of discrete distributions,
+// and that Unsample(P(u)) lies within the interval of u
+func TestMapUnsampleDiscrete(t *testing.T) {
+	mk := func(d Mapper, err error) Mapper {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	maps := []struct {
+		name string
+		m    *Map
+		xs   []float64
+	}{
+		{"discrete uniform", &Map{Low: 0., High: 1., Distr: mk(NewDiscreteUniform(-2, 3))}, []float64{-2., -1., 0., 1., 2., 3.}},
+		{"poisson", &Map{Low: 0., High: 1., Distr: mk(NewPoisson(3.5))}, []float64{0., 1., 2., 5., 10.}},
+		{"binomial", &Map{Low: 0., High: 1., Distr: mk(NewBinomial(6, .3))}, []float64{0., 1., 3., 6.}},
+		{"categorical", &Map{Low: 0., High: 1., Distr: mk(NewCategorical([]float64{1., 0., 3., 2.}))}, []float64{0., 2., 3.}},
+	}
+	for _, c := range maps {
+		for _, x := range c.xs {
+			if got := c.m.P(c.m.Unsample(x)); got != x {
+				t.Errorf("%s: P(Unsample(%v)) = %v", c.name, x, got)
+			}
+		}
+		for _, u := range []float64{.001, .1, .3, .5, .7, .9, .999} {
+			x := c.m.P(u)
+			if lo := c.m.CDF(x) - c.m.PDF(x); u < lo-1e-12 || u > c.m.CDF(x) {
+				t.Errorf("%s: u = %v outside [F(%v-1), F(%v)) = [%v, %v)", c.name, u, x, x, lo, c.m.CDF(x))
+			}
+			if got := c.m.P(c.m.Unsample(x)); got != x {
+				t.Errorf("%s: P(Unsample(P(%v))) = %v, want %v", c.name, u, got, x)
+			}
+		}
+	}
+}
+
+// TestTrapezoidInvalid checks that Inv returns NaN, rather than panicking, for an invalid Trapezoid or u
+func TestTrapezoidInvalid(t *testing.T) {
+	for _, c := range []struct {
+		d Mapper
+		u float64
+	}{{&Trapezoid{}, .5}, {&Triangle{m: 1.5}, .5}, {NewTriangle(.3), 1.5}, {NewTriangle(.3), math.NaN()}} {
+		if x := c.d.Inv(c.u); !math.IsNaN(x) {
+			t.Errorf("%+v.Inv(%v) = %v", c.d, c.u, x)
+		}
+	}
+	for _, m := range []float64{0., 1.} {
+		tr := NewTriangle(m)
+		for _, u := range []float64{0., .5, 1.} {
+			if x := tr.Inv(u); math.IsNaN(x) || math.Abs(tr.CDF(x)-u) > 1e-12 {
+				t.Errorf("NewTriangle(%v).Inv(%v) = %v", m, u, x)
+			}
+		}
+	}
+}
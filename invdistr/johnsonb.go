@@ -5,43 +5,199 @@
 package invdistr
 
 import (
+	"fmt"
 	"log"
 	"math"
 )
 
 // JohnsonB (bounded) sampling distribution
 type JohnsonB struct {
-	m float64
+	m      float64
+	a1, a2 float64 // shape parameters alpha1 and alpha2
 }
 
+// defaultPeak is the target density at the mode used to set alpha2
+const defaultPeak = 4.
+
+// minAlpha2 is the smallest alpha2 used when targeting a peak density, below which the distribution may be bimodal
+// see Johnson, N.L., 1949. Systems of frequency curves generated by methods of translation. Biometrika 36. pp.149-176.
+var minAlpha2 = 1. / math.Sqrt2
+
 // NewJohnsonB constructor
 func NewJohnsonB(m float64) *JohnsonB {
-	if m < 0.0 || m > 1.0 {
-		log.Panicf("Invalid JohnsonB parameter m: %v\n", m)
+	j, err := NewJohnsonBE(m)
+	if err != nil {
+		log.Panicln(err)
+	}
+	return j
+}
+
+// NewJohnsonBE constructor with mode m [0,1], returning an error for invalid arguments.
+// alpha1 and alpha2 are searched (in base-10 logarithms, in 0.01 increments of alpha2) as originally,
+// such that earlier sampling results are repeated; the resulting mode is near, but not, m
+// (see NewJohnsonBMode for a distribution whose mode is m)
+func NewJohnsonBE(m float64) (*JohnsonB, error) {
+	if m < 0.0 || m > 1.0 || math.IsNaN(m) {
+		return nil, fmt.Errorf("invdistr.NewJohnsonB: invalid argument m = %v: %w", m, ErrInvalidShape)
 	} else if m == 0. {
 		m = 0.01
 	} else if m == 1. {
 		m = 0.99
 	}
-	j := new(JohnsonB)
-	j.m = m
-	return j
+	l := math.Log10(m / (1. - m))
+	a1, a2 := 0., 0.69 // a2 < 0.7 results in bimodal distributions
+	for {
+		a2 += 0.01
+		a1 = (2.*m-1.)/a2 - a2*l
+		if a2/m/(1.-m)/math.Sqrt(2.*math.Pi)*math.Exp(-0.5*math.Pow(a1+a2*l, 2.0)) > defaultPeak {
+			break
+		}
+	}
+	return &JohnsonB{m: m, a1: a1, a2: a2}, nil
+}
+
+// NewJohnsonBMode constructor with mode m [0,1] and a density of 4 at the mode (see NewJohnsonBPeak),
+// returning an error for invalid arguments
+func NewJohnsonBMode(m float64) (*JohnsonB, error) {
+	if m < 0.0 || m > 1.0 || math.IsNaN(m) {
+		return nil, fmt.Errorf("invdistr.NewJohnsonBMode: invalid argument m = %v: %w", m, ErrInvalidShape)
+	} else if m == 0. {
+		m = 0.01
+	} else if m == 1. {
+		m = 0.99
+	}
+	a2, err := peakAlpha2(m, defaultPeak)
+	if err != nil {
+		return nil, err
+	}
+	return &JohnsonB{m: m, a1: modeAlpha1(m, a2), a2: a2}, nil
+}
+
+// NewJohnsonBSpread constructor with mode m (0,1) and spread parameter alpha2
+// (increase alpha2 for smaller variance about the mode; alpha2 < 1/√2 may result in bimodal distributions)
+func NewJohnsonBSpread(m, alpha2 float64) (*JohnsonB, error) {
+	if m <= 0. || m >= 1. || alpha2 <= 0. || math.IsInf(alpha2, 0) {
+		return nil, fmt.Errorf("invdistr.NewJohnsonBSpread: invalid arguments m, alpha2 = %v, %v: %w", m, alpha2, ErrInvalidShape)
+	}
+	return &JohnsonB{m: m, a1: modeAlpha1(m, alpha2), a2: alpha2}, nil
+}
+
+// NewJohnsonBPeak constructor with mode m (0,1), where alpha2 is set such that the density
+// at the mode equals peak (NewJohnsonB uses a peak of 4); alpha2 is held at no less than 1/√2,
+// in which case the density at the mode exceeds peak
+func NewJohnsonBPeak(m, peak float64) (*JohnsonB, error) {
+	if m <= 0. || m >= 1. || peak <= 0. || math.IsInf(peak, 0) {
+		return nil, fmt.Errorf("invdistr.NewJohnsonBPeak: invalid arguments m, peak = %v, %v: %w", m, peak, ErrInvalidShape)
+	}
+	a2, err := peakAlpha2(m, peak)
+	if err != nil {
+		return nil, err
+	}
+	return &JohnsonB{m: m, a1: modeAlpha1(m, a2), a2: a2}, nil
+}
+
+// NewJohnsonBShape constructor directly from shape parameters alpha1 and alpha2,
+// where u = Φ(alpha1 + alpha2 ln(x/(1-x)))
+func NewJohnsonBShape(alpha1, alpha2 float64) (*JohnsonB, error) {
+	if alpha2 <= 0. || math.IsNaN(alpha1) || math.IsInf(alpha1, 0) {
+		return nil, fmt.Errorf("invdistr.NewJohnsonBShape: invalid arguments alpha1, alpha2 = %v, %v: %w", alpha1, alpha2, ErrInvalidShape)
+	}
+	return &JohnsonB{a1: alpha1, a2: alpha2}, nil
 }
 
+// Shape returns the shape parameters alpha1 and alpha2
+func (t *JohnsonB) Shape() (float64, float64) { return t.a1, t.a2 }
+
+// Mode returns the mode m given at construction (nominal for NewJohnsonB; 0 when constructed from its shape parameters)
+func (t *JohnsonB) Mode() float64 { return t.m }
+
 // Inv : inverse function
-// setting parameter alpha2 to 2.0 (increase number for smaller variance about the mode)
 func (t *JohnsonB) Inv(f float64) float64 {
-	var a1, y float64
-	a2 := 0.69 // a2 < 0.7 results in bimodal distributions
-loop:
-	a2 += 0.01
-	a1 = (2.*t.m-1.)/a2 - a2*math.Log10(t.m/(1.-t.m))
-	y = a2 / t.m / (1. - t.m) / math.Sqrt(2.*math.Pi) * math.Exp(-0.5*math.Pow(a1+a2*math.Log10(t.m/(1.-t.m)), 2.0))
-	if y <= 4. { // search for alpha2 such that f(x)=4
-		goto loop
+	if f <= 0. {
+		return 0.
+	} else if f >= 1. {
+		return 1.
 	}
-
 	z := math.Sqrt(2.) * math.Erfinv(2.*f-1.)
-	y = math.Exp((z - a1) / a2)
+	y := math.Exp((z - t.a1) / t.a2)
 	return y / (y + 1.)
 }
+
+// modeAlpha1 returns alpha1 given mode m and alpha2, from the stationary point of the density: 2m-1 = alpha2(alpha1 + alpha2 ln(m/(1-m)))
+func modeAlpha1(m, a2 float64) float64 {
+	return (2.*m-1.)/a2 - a2*math.Log(m/(1.-m))
+}
+
+// peakAlpha2 returns alpha2 (no less than minAlpha2) such that the density at mode m equals peak,
+// where the density at the mode, alpha2 φ((2m-1)/alpha2) / m(1-m), increases monotonically with alpha2
+func peakAlpha2(m, peak float64) (float64, error) {
+	f := func(a2 float64) float64 { return a2*normPDF((2.*m-1.)/a2)/m/(1.-m) - peak }
+	if f(minAlpha2) >= 0. {
+		return minAlpha2, nil
+	}
+	hi := 2. * minAlpha2
+	for i := 0; f(hi) < 0.; i++ {
+		if i == 1000 {
+			return 0., fmt.Errorf("invdistr.NewJohnsonBPeak: no alpha2 found for m, peak = %v, %v: %w", m, peak, ErrInvalidShape)
+		}
+		hi *= 2.
+	}
+	return bisect(f, minAlpha2, hi), nil
+}
+
+// PDF : probability density function
+func (t *JohnsonB) PDF(x float64) float64 {
+	if x <= 0. || x >= 1. {
+		return 0.
+	}
+	return t.a2 / x / (1. - x) * normPDF(t.a1+t.a2*math.Log(x/(1.-x)))
+}
+
+// CDF : cumulative distribution function
+func (t *JohnsonB) CDF(x float64) float64 {
+	if x <= 0. {
+		return 0.
+	}
+	if x >= 1. {
+		return 1.
+	}
+	return normCDF(t.a1 + t.a2*math.Log(x/(1.-x)))
+}
+
+// Mean of the distribution
+func (t *JohnsonB) Mean() float64 {
+	m, _ := t.moments()
+	return m
+}
+
+// Variance of the distribution
+func (t *JohnsonB) Variance() float64 {
+	m, m2 := t.moments()
+	return m2 - m*m
+}
+
+// moments returns the first two raw moments, integrated numerically (Simpson's rule) over the standard normal variate
+func (t *JohnsonB) moments() (float64, float64) {
+	const n, zl = 2000, 10.
+	a1, a2 := t.a1, t.a2
+	h := 2. * zl / n
+	m, m2 := 0., 0.
+	for i := 0; i <= n; i++ {
+		z := -zl + float64(i)*h
+		w := 2.
+		switch {
+		case i == 0 || i == n:
+			w = 1.
+		case i%2 == 1:
+			w = 4.
+		}
+		x := 1. / (1. + math.Exp(-(z-a1)/a2))
+		p := w * normPDF(z)
+		m += p * x
+		m2 += p * x * x
+	}
+	return m * h / 3., m2 * h / 3.
+}
+
+// Support returns the range of the distribution
+func (t *JohnsonB) Support() (float64, float64) { return 0., 1. }
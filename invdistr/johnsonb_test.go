@@ -0,0 +1,124 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// TestJohnsonBPeak checks that the mode and peak density of NewJohnsonBPeak are those requested
+func TestJohnsonBPeak(t *testing.T) {
+	for _, m := range []float64{.01, .1, .2, .5, .75, .95} {
+		for _, peak := range []float64{2., 4., 10., 1e6} {
+			j, err := NewJohnsonBPeak(m, peak)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// locate the mode numerically
+			xm, pm := 0., 0.
+			for i := 1; i < 100000; i++ {
+				x := float64(i) / 100000.
+				if p := j.PDF(x); p > pm {
+					xm, pm = x, p
+				}
+			}
+			if math.Abs(xm-m) > 1e-4 {
+				t.Errorf("NewJohnsonBPeak(%v, %v): mode = %v", m, peak, xm)
+			}
+			_, a2 := j.Shape()
+			if p := j.PDF(m); relErr(p, peak) > 1e-8 && !(a2 == minAlpha2 && p > peak) {
+				t.Errorf("NewJohnsonBPeak(%v, %v): density at the mode = %v", m, peak, p)
+			}
+		}
+	}
+	if _, err := NewJohnsonBPeak(.5, math.Inf(1)); err == nil {
+		t.Error("NewJohnsonBPeak: infinite peak accepted")
+	}
+}
+
+// TestJohnsonBSpread checks that the mode of NewJohnsonBSpread is m
+func TestJohnsonBSpread(t *testing.T) {
+	for _, m := range []float64{.1, .3, .6} {
+		j, err := NewJohnsonBSpread(m, 1.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := 1e-6
+		if d := j.PDF(m+h) - j.PDF(m-h); math.Abs(d) > 1e-6*j.PDF(m) {
+			t.Errorf("NewJohnsonBSpread(%v, 1.5): density not stationary at the mode", m)
+		}
+	}
+}
+
+// TestJohnsonBOriginal checks that NewJohnsonB reproduces the original search
+func TestJohnsonBOriginal(t *testing.T) {
+	for _, m := range []float64{0., .1, .2, .5, .9} {
+		j, err := NewJohnsonBE(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mm := math.Min(math.Max(m, .01), .99)
+		var a1, y float64
+		a2 := 0.69
+		for y <= 4. {
+			a2 += 0.01
+			a1 = (2.*mm-1.)/a2 - a2*math.Log10(mm/(1.-mm))
+			y = a2 / mm / (1. - mm) / math.Sqrt(2.*math.Pi) * math.Exp(-0.5*math.Pow(a1+a2*math.Log10(mm/(1.-mm)), 2.0))
+		}
+		for _, u := range []float64{.01, .3, .5, .9} {
+			z := math.Sqrt(2.) * math.Erfinv(2.*u-1.)
+			e := math.Exp((z - a1) / a2)
+			if x := j.Inv(u); x != e/(e+1.) {
+				t.Errorf("NewJohnsonB(%v).Inv(%v) = %v, want %v", m, u, x, e/(e+1.))
+			}
+		}
+	}
+}
+
+// TestJohnsonBMedian checks the medians of NewJohnsonB (original) and NewJohnsonBMode, and the bounds of Inv
+func TestJohnsonBMedian(t *testing.T) {
+	for _, c := range []struct{ m, orig, mode float64 }{{.1, .4135, .1691}, {.8, .5979, .7650}} {
+		j := NewJohnsonB(c.m)
+		if x := j.Inv(.5); math.Abs(x-c.orig) > 5e-5 {
+			t.Errorf("NewJohnsonB(%v): median = %v, want %v", c.m, x, c.orig)
+		}
+		k, err := NewJohnsonBMode(c.m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x := k.Inv(.5); math.Abs(x-c.mode) > 5e-5 {
+			t.Errorf("NewJohnsonBMode(%v): median = %v, want %v", c.m, x, c.mode)
+		}
+		for _, d := range []*JohnsonB{j, k} {
+			if x0, x1 := d.Inv(0.), d.Inv(1.); x0 != 0. || x1 != 1. {
+				t.Errorf("m = %v: Inv(0), Inv(1) = %v, %v", c.m, x0, x1)
+			}
+		}
+		m := &Map{Low: 2., High: 5., Distr: k}
+		if lo, hi := m.P(0.), m.P(1.); lo != 2. || hi != 5. {
+			t.Errorf("NewJohnsonBMode(%v): mapped range %v, %v", c.m, lo, hi)
+		}
+	}
+}
+
+// TestJohnsonBSpec checks that the mode survives a Spec round trip
+func TestJohnsonBSpec(t *testing.T) {
+	j, err := NewJohnsonBPeak(.3, 3.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := SpecOf(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.Mapper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := d.(*JohnsonB)
+	if r.Mode() != .3 || r.a1 != j.a1 || r.a2 != j.a2 {
+		t.Errorf("johnsonb spec %v restored as m, a1, a2 = %v, %v, %v", s.Params, r.m, r.a1, r.a2)
+	}
+	if _, err := (Spec{Kind: "johnsonb", Params: []float64{j.a1, j.a2, 1.}}).Mapper(); err == nil {
+		t.Error("johnsonb spec with mode 1 accepted")
+	}
+}
@@ -0,0 +1,105 @@
+// kernel.go returns a kernel density (smoothed empirical) probability distribution
+// of a data set from u[0,1], using a Gaussian kernel. The inverse is solved numerically.
+// KernelDensity is unbounded and returns values in parameter space (use with a Map where Low=0, High=1).
+// see Silverman, B.W., 1986. Density Estimation for Statistics and Data Analysis. Chapman & Hall. 175pp.
+
+package invdistr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// KernelDensity sampling distribution
+type KernelDensity struct {
+	x []float64 // sorted data
+	h float64   // bandwidth
+}
+
+// NewKernelDensity constructor from data with kernel bandwidth h,
+// setting h <= 0 uses Silverman's rule of thumb
+func NewKernelDensity(data []float64, h float64) (*KernelDensity, error) {
+	n := len(data)
+	if n < 2 {
+		return nil, fmt.Errorf("invdistr.NewKernelDensity: at least 2 data required, %d given: %w", n, ErrDimensionMismatch)
+	}
+	x := make([]float64, n)
+	copy(x, data)
+	sort.Float64s(x)
+	if h <= 0. {
+		_, sd, _ := SampleMoments(x)
+		q := func(p float64) float64 { // interpolated sample quantile
+			r := p * float64(n-1)
+			i := int(r)
+			if i >= n-1 {
+				return x[n-1]
+			}
+			return x[i] + (r-float64(i))*(x[i+1]-x[i])
+		}
+		s := sd
+		if iqr := (q(.75) - q(.25)) / 1.34; iqr > 0. && iqr < s {
+			s = iqr
+		}
+		h = .9 * s * math.Pow(float64(n), -.2)
+	}
+	if h <= 0. || math.IsNaN(h) {
+		return nil, fmt.Errorf("invdistr.NewKernelDensity: invalid bandwidth %v (data have no spread): %w", h, ErrInvalidShape)
+	}
+	return &KernelDensity{x: x, h: h}, nil
+}
+
+// Bandwidth returns the kernel bandwidth
+func (t *KernelDensity) Bandwidth() float64 { return t.h }
+
+// Inv : inverse function
+func (t *KernelDensity) Inv(u float64) float64 {
+	if u <= 0. {
+		return math.Inf(-1)
+	}
+	if u >= 1. {
+		return math.Inf(1)
+	}
+	// the mixture quantile is bracketed by the kernel quantiles of the extreme data
+	z := t.h * normQuantile(u)
+	return bisect(func(x float64) float64 { return t.CDF(x) - u }, t.x[0]+z, t.x[len(t.x)-1]+z)
+}
+
+// PDF : probability density function
+func (t *KernelDensity) PDF(x float64) float64 {
+	s := 0.
+	for _, v := range t.x {
+		s += normPDF((x - v) / t.h)
+	}
+	return s / float64(len(t.x)) / t.h
+}
+
+// CDF : cumulative distribution function
+func (t *KernelDensity) CDF(x float64) float64 {
+	s := 0.
+	for _, v := range t.x {
+		s += normCDF((x - v) / t.h)
+	}
+	return s / float64(len(t.x))
+}
+
+// Mean of the distribution
+func (t *KernelDensity) Mean() float64 {
+	m := 0.
+	for _, v := range t.x {
+		m += v
+	}
+	return m / float64(len(t.x))
+}
+
+// Variance of the distribution
+func (t *KernelDensity) Variance() float64 {
+	m, s := t.Mean(), 0.
+	for _, v := range t.x {
+		s += (v - m) * (v - m)
+	}
+	return s/float64(len(t.x)) + t.h*t.h
+}
+
+// Support returns the range of the distribution
+func (t *KernelDensity) Support() (float64, float64) { return math.Inf(-1), math.Inf(1) }
@@ -0,0 +1,150 @@
+// lognormal.go returns LogNormal probability distributions from u[0,1], parameterized
+// by the mean mu and standard deviation sigma of the natural logarithm of the variate.
+// LogNormal returns values in parameter space (use with a Map where Low=0, High=1);
+// TruncatedLogNormal is bounded to [low, high] and returns the relative position
+// within the bounds such that it composes with Map{Low: low, High: high}.
+
+package invdistr
+
+import (
+	"fmt"
+	"math"
+)
+
+// LogNormal sampling distribution
+type LogNormal struct {
+	mu, sigma float64
+}
+
+// NewLogNormal constructor, where mu and sigma are the mean and standard deviation of ln(x)
+func NewLogNormal(mu, sigma float64) (*LogNormal, error) {
+	if sigma <= 0. || math.IsInf(mu, 0) || math.IsNaN(mu) {
+		return nil, fmt.Errorf("invdistr.NewLogNormal: invalid arguments mu, sigma = %v, %v: %w", mu, sigma, ErrInvalidShape)
+	}
+	return &LogNormal{mu: mu, sigma: sigma}, nil
+}
+
+// NewLogNormalMoments constructor from the (arithmetic) mean and standard deviation of x
+func NewLogNormalMoments(mean, sd float64) (*LogNormal, error) {
+	if mean <= 0. || sd <= 0. {
+		return nil, fmt.Errorf("invdistr.NewLogNormalMoments: invalid arguments mean, sd = %v, %v: %w", mean, sd, ErrInvalidShape)
+	}
+	s2 := math.Log(1. + sd*sd/mean/mean)
+	return NewLogNormal(math.Log(mean)-s2/2., math.Sqrt(s2))
+}
+
+// Inv : inverse function
+func (t *LogNormal) Inv(u float64) float64 {
+	return math.Exp(t.mu + t.sigma*normQuantile(u))
+}
+
+// PDF : probability density function
+func (t *LogNormal) PDF(x float64) float64 {
+	if x <= 0. {
+		return 0.
+	}
+	return normPDF((math.Log(x)-t.mu)/t.sigma) / t.sigma / x
+}
+
+// CDF : cumulative distribution function
+func (t *LogNormal) CDF(x float64) float64 {
+	if x <= 0. {
+		return 0.
+	}
+	return normCDF((math.Log(x) - t.mu) / t.sigma)
+}
+
+// Mean of the distribution
+func (t *LogNormal) Mean() float64 { return math.Exp(t.mu + t.sigma*t.sigma/2.) }
+
+// Variance of the distribution
+func (t *LogNormal) Variance() float64 {
+	s2 := t.sigma * t.sigma
+	return (math.Exp(s2) - 1.) * math.Exp(2.*t.mu+s2)
+}
+
+// Support returns the range of the distribution
+func (t *LogNormal) Support() (float64, float64) { return 0., math.Inf(1) }
+
+// TruncatedLogNormal sampling distribution
+type TruncatedLogNormal struct {
+	mu, sigma, low, high float64
+	a, b, z              float64 // standardized (log) bounds and probability mass within bounds
+}
+
+// NewTruncatedLogNormal constructor of a lognormal distribution (mu and sigma of ln(x) of the
+// parent, untruncated, distribution) truncated to [low, high], where 0 <= low < high
+func NewTruncatedLogNormal(mu, sigma, low, high float64) (*TruncatedLogNormal, error) {
+	if sigma <= 0. || low < 0. || low >= high || math.IsInf(high, 0) {
+		return nil, fmt.Errorf("invdistr.NewTruncatedLogNormal: invalid arguments mu, sigma, low, high = %v, %v, %v, %v: %w", mu, sigma, low, high, ErrInvalidBounds)
+	}
+	t := &TruncatedLogNormal{mu: mu, sigma: sigma, low: low, high: high}
+	t.a, t.b = (math.Log(low)-mu)/sigma, (math.Log(high)-mu)/sigma
+	t.z = normCDF(t.b) - normCDF(t.a)
+	if t.a > 0. {
+		t.z = normCDF(-t.a) - normCDF(-t.b)
+	}
+	if t.z <= 0. {
+		return nil, fmt.Errorf("invdistr.NewTruncatedLogNormal: no probability mass within [%v, %v] for mu, sigma = %v, %v: %w", low, high, mu, sigma, ErrInvalidBounds)
+	}
+	return t, nil
+}
+
+// Inv : inverse function, returns the relative position within [low, high]
+func (t *TruncatedLogNormal) Inv(u float64) float64 {
+	x := math.Exp(t.mu + t.sigma*normTruncQuantile(t.a, t.b, u))
+	return math.Min(math.Max((x-t.low)/(t.high-t.low), 0.), 1.)
+}
+
+// PDF : probability density function of relative position x within [low, high]
+func (t *TruncatedLogNormal) PDF(x float64) float64 {
+	if x < 0. || x > 1. {
+		return 0.
+	}
+	w := t.high - t.low
+	v := t.low + x*w
+	if v <= 0. {
+		return 0.
+	}
+	return normPDF((math.Log(v)-t.mu)/t.sigma) / t.sigma / v / t.z * w
+}
+
+// CDF : cumulative distribution function of relative position x within [low, high]
+func (t *TruncatedLogNormal) CDF(x float64) float64 {
+	if x <= 0. {
+		return 0.
+	}
+	if x >= 1. {
+		return 1.
+	}
+	zx := (math.Log(t.low+x*(t.high-t.low)) - t.mu) / t.sigma
+	if t.a > 0. {
+		return (normCDF(-t.a) - normCDF(-zx)) / t.z
+	}
+	return (normCDF(zx) - normCDF(t.a)) / t.z
+}
+
+// rawMoment returns E[x^k] of the truncated distribution in parameter space
+func (t *TruncatedLogNormal) rawMoment(k float64) float64 {
+	ks := k * t.sigma
+	m := normCDF(t.b-ks) - normCDF(t.a-ks)
+	if t.a-ks > 0. {
+		m = normCDF(ks-t.a) - normCDF(ks-t.b)
+	}
+	return math.Exp(k*t.mu+ks*ks/2.) * m / t.z
+}
+
+// Mean of the distribution (relative to [low, high])
+func (t *TruncatedLogNormal) Mean() float64 {
+	return (t.rawMoment(1.) - t.low) / (t.high - t.low)
+}
+
+// Variance of the distribution (relative to [low, high])
+func (t *TruncatedLogNormal) Variance() float64 {
+	m := t.rawMoment(1.)
+	w := t.high - t.low
+	return (t.rawMoment(2.) - m*m) / w / w
+}
+
+// Support returns the range of the distribution
+func (t *TruncatedLogNormal) Support() (float64, float64) { return 0., 1. }
@@ -0,0 +1,53 @@
+// moments.go computes sample moments and L-moments used to parameterize distributions from data.
+// see Hosking, J.R.M. and J.R. Wallis, 1997. Regional Frequency Analysis: An Approach Based on L-Moments. Cambridge University Press. 224pp.
+
+package invdistr
+
+import (
+	"math"
+	"sort"
+)
+
+// SampleMoments returns the sample mean, standard deviation and (bias-corrected) skewness of x
+func SampleMoments(x []float64) (mean, sd, skew float64) {
+	n := float64(len(x))
+	for _, v := range x {
+		mean += v
+	}
+	mean /= n
+	m2, m3 := 0., 0.
+	for _, v := range x {
+		d := v - mean
+		m2 += d * d
+		m3 += d * d * d
+	}
+	sd = math.Sqrt(m2 / (n - 1.))
+	skew = n / (n - 1.) / (n - 2.) * m3 / sd / sd / sd
+	return
+}
+
+// SampleLMoments returns the first two sample L-moments (l1: mean, l2: L-scale) and
+// the L-moment ratios t3 (L-skewness) and t4 (L-kurtosis) of x, using unbiased probability weighted moments
+func SampleLMoments(x []float64) (l1, l2, t3, t4 float64) {
+	s := make([]float64, len(x))
+	copy(s, x)
+	sort.Float64s(s)
+	n := float64(len(s))
+	var b0, b1, b2, b3 float64
+	for i, v := range s {
+		j := float64(i) // j = rank-1
+		b0 += v
+		b1 += v * j / (n - 1.)
+		b2 += v * j * (j - 1.) / (n - 1.) / (n - 2.)
+		b3 += v * j * (j - 1.) * (j - 2.) / (n - 1.) / (n - 2.) / (n - 3.)
+	}
+	b0 /= n
+	b1 /= n
+	b2 /= n
+	b3 /= n
+	l1 = b0
+	l2 = 2.*b1 - b0
+	t3 = (6.*b2 - 6.*b1 + b0) / l2
+	t4 = (20.*b3 - 30.*b2 + 12.*b1 - b0) / l2
+	return
+}
@@ -0,0 +1,119 @@
+// normal.go returns Normal (Gaussian) probability distributions from u[0,1].
+// Normal is unbounded and returns values in parameter space (use with a Map where Low=0, High=1;
+// or set Map.Log for a base-10 lognormal); TruncatedNormal is bounded to [low, high]
+// and returns the relative position within the bounds such that it composes with Map{Low: low, High: high}.
+
+package invdistr
+
+import (
+	"fmt"
+	"math"
+)
+
+// Normal sampling distribution
+type Normal struct {
+	mu, sigma float64
+}
+
+// NewNormal constructor with mean mu and standard deviation sigma
+func NewNormal(mu, sigma float64) (*Normal, error) {
+	if sigma <= 0. || math.IsInf(mu, 0) || math.IsNaN(mu) {
+		return nil, fmt.Errorf("invdistr.NewNormal: invalid arguments mu, sigma = %v, %v: %w", mu, sigma, ErrInvalidShape)
+	}
+	return &Normal{mu: mu, sigma: sigma}, nil
+}
+
+// Inv : inverse function
+func (t *Normal) Inv(u float64) float64 {
+	return t.mu + t.sigma*normQuantile(u)
+}
+
+// PDF : probability density function
+func (t *Normal) PDF(x float64) float64 {
+	return normPDF((x-t.mu)/t.sigma) / t.sigma
+}
+
+// CDF : cumulative distribution function
+func (t *Normal) CDF(x float64) float64 {
+	return normCDF((x - t.mu) / t.sigma)
+}
+
+// Mean of the distribution
+func (t *Normal) Mean() float64 { return t.mu }
+
+// Variance of the distribution
+func (t *Normal) Variance() float64 { return t.sigma * t.sigma }
+
+// Support returns the range of the distribution
+func (t *Normal) Support() (float64, float64) { return math.Inf(-1), math.Inf(1) }
+
+// TruncatedNormal sampling distribution
+type TruncatedNormal struct {
+	mu, sigma, low, high float64
+	a, b, z              float64 // standardized bounds and probability mass within bounds
+}
+
+// NewTruncatedNormal constructor of a normal distribution with mean mu and standard deviation sigma
+// (of the parent, untruncated, distribution) truncated to [low, high]
+func NewTruncatedNormal(mu, sigma, low, high float64) (*TruncatedNormal, error) {
+	if sigma <= 0. || low >= high || math.IsInf(low, 0) || math.IsInf(high, 0) {
+		return nil, fmt.Errorf("invdistr.NewTruncatedNormal: invalid arguments mu, sigma, low, high = %v, %v, %v, %v: %w", mu, sigma, low, high, ErrInvalidBounds)
+	}
+	t := &TruncatedNormal{mu: mu, sigma: sigma, low: low, high: high}
+	t.a, t.b = (low-mu)/sigma, (high-mu)/sigma
+	t.z = normCDF(t.b) - normCDF(t.a)
+	if t.a > 0. {
+		t.z = normCDF(-t.a) - normCDF(-t.b)
+	}
+	if t.z <= 0. {
+		return nil, fmt.Errorf("invdistr.NewTruncatedNormal: no probability mass within [%v, %v] for mu, sigma = %v, %v: %w", low, high, mu, sigma, ErrInvalidBounds)
+	}
+	return t, nil
+}
+
+// Inv : inverse function, returns the relative position within [low, high]
+func (t *TruncatedNormal) Inv(u float64) float64 {
+	x := t.mu + t.sigma*normTruncQuantile(t.a, t.b, u)
+	return math.Min(math.Max((x-t.low)/(t.high-t.low), 0.), 1.)
+}
+
+// PDF : probability density function of relative position x within [low, high]
+func (t *TruncatedNormal) PDF(x float64) float64 {
+	if x < 0. || x > 1. {
+		return 0.
+	}
+	w := t.high - t.low
+	return normPDF((t.low+x*w-t.mu)/t.sigma) / t.sigma / t.z * w
+}
+
+// CDF : cumulative distribution function of relative position x within [low, high]
+func (t *TruncatedNormal) CDF(x float64) float64 {
+	if x <= 0. {
+		return 0.
+	}
+	if x >= 1. {
+		return 1.
+	}
+	zx := (t.low + x*(t.high-t.low) - t.mu) / t.sigma
+	if t.a > 0. {
+		return (normCDF(-t.a) - normCDF(-zx)) / t.z
+	}
+	return (normCDF(zx) - normCDF(t.a)) / t.z
+}
+
+// Mean of the distribution (relative to [low, high])
+func (t *TruncatedNormal) Mean() float64 {
+	m := t.mu + t.sigma*(normPDF(t.a)-normPDF(t.b))/t.z
+	return (m - t.low) / (t.high - t.low)
+}
+
+// Variance of the distribution (relative to [low, high])
+func (t *TruncatedNormal) Variance() float64 {
+	d := (normPDF(t.a) - normPDF(t.b)) / t.z
+	v := t.sigma * t.sigma * (1. + (t.a*normPDF(t.a)-t.b*normPDF(t.b))/t.z - d*d)
+	w := t.high - t.low
+	return v / w / w
+}
+
+// Support returns the range of the distribution
+func (t *TruncatedNormal) Support() (float64, float64) { return 0., 1. }
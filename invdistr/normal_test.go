@@ -0,0 +1,49 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNormQuantile checks the standard normal quantile against tabulated values, far into the tails
+func TestNormQuantile(t *testing.T) {
+	for _, c := range []struct{ p, z float64 }{
+		{.5, 0.},
+		{.975, 1.959963984540054},
+		{.001, -3.090232306167814},
+		{1e-10, -6.361340902404056},
+		{1e-20, -9.262340089798408},
+		{1e-100, -21.27345356},
+		{1. - 1e-10, 6.361340889697422}, // 1-1e-10 is not exact in floating point
+	} {
+		if z := normQuantile(c.p); relErr(z, c.z) > 1e-9 {
+			t.Errorf("normQuantile(%v) = %.16g, want %.16g", c.p, z, c.z)
+		}
+	}
+	for _, p := range []float64{1e-300, 1e-200, 1e-50, 1e-16, 1e-8, .01, .3} {
+		if f := normCDF(normQuantile(p)); relErr(f, p) > 1e-12 {
+			t.Errorf("normCDF(normQuantile(%v)) = %v", p, f)
+		}
+	}
+	if !math.IsInf(normQuantile(0.), -1) || !math.IsInf(normQuantile(1.), 1) || !math.IsNaN(normQuantile(1.5)) {
+		t.Error("normQuantile at 0, 1 or outside [0,1]")
+	}
+}
+
+// TestTruncatedNormal checks CDF(Inv(u)) = u and the bounds, including truncation far into either tail
+func TestTruncatedNormal(t *testing.T) {
+	for _, c := range [][4]float64{{0., 1., -1., 2.}, {0., 1., 8., 9.}, {0., 1., -9., -8.}, {5., 2., 30., 31.}, {0., 1., 20., 40.}} {
+		tn, err := NewTruncatedNormal(c[0], c[1], c[2], c[3])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x0, x1 := tn.Inv(0.), tn.Inv(1.); math.Abs(x0) > 1e-9 || math.Abs(x1-1.) > 1e-9 {
+			t.Errorf("%v: Inv(0), Inv(1) = %v, %v", c, x0, x1)
+		}
+		for _, u := range []float64{1e-6, .01, .3, .5, .7, .99, 1. - 1e-6} {
+			if f := tn.CDF(tn.Inv(u)); math.Abs(f-u) > 1e-8 {
+				t.Errorf("%v: CDF(Inv(%v)) = %v", c, u, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// piecewise.go returns a piecewise-linear probability distribution from u[0,1], defined by
+// breakpoints (x, F(x)) of its cumulative distribution function. The empirical distribution
+// of a data set is a special case, linearly interpolating between order statistics.
+// Repeated breakpoints x (e.g., tied data) hold a point mass (atom) equal to the jump in F:
+// Inv returns the atom for any u within the jump, CDF is right-continuous (returning the top of
+// the jump at the atom, such that CDF(Inv(u)) >= u), Mean and Variance include the atoms, while
+// PDF returns the density of the continuous part only (integrating to one less the atom masses).
+// PiecewiseLinear returns values in parameter space (use with a Map where Low=0, High=1).
+
+package invdistr
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PiecewiseLinear sampling distribution
+type PiecewiseLinear struct {
+	x, f []float64 // breakpoints
+}
+
+// NewPiecewiseLinear constructor from breakpoints x (non-decreasing) and
+// cumulative probabilities f (non-decreasing from 0 to 1)
+func NewPiecewiseLinear(x, f []float64) (*PiecewiseLinear, error) {
+	n := len(x)
+	if n < 2 || len(f) != n {
+		return nil, fmt.Errorf("invdistr.NewPiecewiseLinear: %d breakpoints given with %d probabilities, at least 2 required: %w", n, len(f), ErrDimensionMismatch)
+	}
+	if f[0] != 0. || f[n-1] != 1. {
+		return nil, fmt.Errorf("invdistr.NewPiecewiseLinear: cumulative probabilities must range from 0 to 1, given %v to %v: %w", f[0], f[n-1], ErrInvalidShape)
+	}
+	if x[0] == x[n-1] {
+		return nil, fmt.Errorf("invdistr.NewPiecewiseLinear: breakpoints have no range: %w", ErrInvalidBounds)
+	}
+	for i := 1; i < n; i++ {
+		if x[i] < x[i-1] || f[i] < f[i-1] {
+			return nil, fmt.Errorf("invdistr.NewPiecewiseLinear: breakpoint %d (%v, %v) decreases from (%v, %v): %w", i, x[i], f[i], x[i-1], f[i-1], ErrInvalidShape)
+		}
+	}
+	t := &PiecewiseLinear{x: make([]float64, n), f: make([]float64, n)}
+	copy(t.x, x)
+	copy(t.f, f)
+	return t, nil
+}
+
+// NewEmpirical constructor of the empirical distribution of data,
+// linearly interpolating between order statistics (bounded by the data minimum and maximum);
+// k tied values hold an atom of probability (k-1)/(n-1)
+func NewEmpirical(data []float64) (*PiecewiseLinear, error) {
+	n := len(data)
+	if n < 2 {
+		return nil, fmt.Errorf("invdistr.NewEmpirical: at least 2 data required, %d given: %w", n, ErrDimensionMismatch)
+	}
+	x, f := make([]float64, n), make([]float64, n)
+	copy(x, data)
+	sort.Float64s(x)
+	for i := range f {
+		f[i] = float64(i) / float64(n-1)
+	}
+	return NewPiecewiseLinear(x, f)
+}
+
+// Inv : inverse function
+func (t *PiecewiseLinear) Inv(u float64) float64 {
+	n := len(t.f)
+	i := sort.Search(n, func(i int) bool { return t.f[i] >= u }) // first breakpoint where F >= u
+	switch {
+	case i == 0:
+		return t.x[0]
+	case i == n:
+		return t.x[n-1]
+	}
+	return t.x[i-1] + (u-t.f[i-1])/(t.f[i]-t.f[i-1])*(t.x[i]-t.x[i-1])
+}
+
+// PDF : probability density function (of the continuous part, excluding atoms)
+func (t *PiecewiseLinear) PDF(x float64) float64 {
+	n := len(t.x)
+	i := sort.Search(n, func(i int) bool { return t.x[i] > x })
+	if i == 0 || i == n {
+		return 0.
+	}
+	return (t.f[i] - t.f[i-1]) / (t.x[i] - t.x[i-1])
+}
+
+// CDF : cumulative distribution function
+func (t *PiecewiseLinear) CDF(x float64) float64 {
+	n := len(t.x)
+	i := sort.Search(n, func(i int) bool { return t.x[i] > x })
+	switch {
+	case i == 0:
+		return 0.
+	case i == n:
+		return 1.
+	}
+	return t.f[i-1] + (x-t.x[i-1])/(t.x[i]-t.x[i-1])*(t.f[i]-t.f[i-1])
+}
+
+// Mean of the distribution
+func (t *PiecewiseLinear) Mean() float64 {
+	m := 0.
+	for i := 1; i < len(t.x); i++ {
+		m += (t.f[i] - t.f[i-1]) * (t.x[i] + t.x[i-1]) / 2.
+	}
+	return m
+}
+
+// Variance of the distribution
+func (t *PiecewiseLinear) Variance() float64 {
+	m, m2 := t.Mean(), 0.
+	for i := 1; i < len(t.x); i++ {
+		a, b := t.x[i-1], t.x[i]
+		m2 += (t.f[i] - t.f[i-1]) * (a*a + a*b + b*b) / 3.
+	}
+	return m2 - m*m
+}
+
+// Support returns the range of the distribution
+func (t *PiecewiseLinear) Support() (float64, float64) { return t.x[0], t.x[len(t.x)-1] }
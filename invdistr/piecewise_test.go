@@ -0,0 +1,44 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEmpiricalTies checks the point mass held by tied data
+func TestEmpiricalTies(t *testing.T) {
+	e, err := NewEmpirical([]float64{3., 2., 1., 2., 2.}) // knots (1,0) (2,.25) (2,.5) (2,.75) (3,1): atom of .5 at 2
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []float64{.25, .4, .6, .75} {
+		if x := e.Inv(u); x != 2. {
+			t.Errorf("Inv(%v) = %v, want the atom 2", u, x)
+		}
+		if f := e.CDF(e.Inv(u)); f != .75 {
+			t.Errorf("CDF(Inv(%v)) = %v, want the top of the jump .75", u, f)
+		}
+	}
+	if f := e.CDF(2. - 1e-12); math.Abs(f-.25) > 1e-9 {
+		t.Errorf("CDF below the atom = %v", f)
+	}
+	for _, u := range []float64{.1, .9} {
+		if f := e.CDF(e.Inv(u)); math.Abs(f-u) > 1e-12 {
+			t.Errorf("CDF(Inv(%v)) = %v", u, f)
+		}
+	}
+	p, h := 0., 1e-4 // density of the continuous part integrates to 1 less the atom
+	for x := 1. + h/2.; x < 3.; x += h {
+		p += e.PDF(x) * h
+	}
+	if math.Abs(p-.5) > 1e-3 {
+		t.Errorf("PDF integrates to %v, want .5", p)
+	}
+	// mean and variance: .25 uniform on [1,2], .5 at 2, .25 uniform on [2,3]
+	if m := e.Mean(); math.Abs(m-2.) > 1e-12 {
+		t.Errorf("Mean = %v", m)
+	}
+	if v := e.Variance(); math.Abs(v-(.5*(1./3.))) > 1e-12 {
+		t.Errorf("Variance = %v", v)
+	}
+}
@@ -0,0 +1,333 @@
+// spec.go provides a serializable description of the distributions such that a Map
+// (and any Sampler wrapping one) can be saved to, and restored from, gob and JSON files.
+
+package invdistr
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Spec is a serializable specification of a distribution by kind and parameters
+type Spec struct {
+	Kind       string    `json:"kind" yaml:"kind"`
+	Params     []float64 `json:"params,omitempty" yaml:"params,omitempty,flow"`
+	Components []Spec    `json:"components,omitempty" yaml:"components,omitempty"` // mixture components (Params hold their weights)
+}
+
+// Kinds lists the distribution kinds of a Spec and their parameters
+var Kinds = map[string]string{
+	"uniform":         "",
+	"johnsonb":        "alpha1, alpha2[, m]",
+	"trapezoid":       "m, n, a, b",
+	"triangle":        "m",
+	"normal":          "mu, sigma",
+	"truncnormal":     "mu, sigma, low, high",
+	"studentt":        "nu, mu, sigma",
+	"lognormal":       "mu, sigma (of ln x)",
+	"trunclognormal":  "mu, sigma (of ln x), low, high",
+	"beta":            "a, b",
+	"pert":            "min, mode, max",
+	"gamma":           "k, theta",
+	"weibull":         "k, lambda",
+	"gumbel":          "mu, beta",
+	"gev":             "mu, sigma, xi",
+	"pearson3":        "mean, sd, skew",
+	"logpearson3":     "mean, sd, skew (of log10 x)",
+	"johnson":         "family (0:SN, 1:SL, 2:SB, 3:SU), gamma, delta, xi, lambda",
+	"piecewise":       "x1, ..., xn, F1, ..., Fn",
+	"kernel":          "h, x1, ..., xn",
+	"discreteuniform": "lo, hi",
+	"poisson":         "lambda",
+	"binomial":        "n, p",
+	"categorical":     "w1, ..., wn",
+	"mixture":         "w1, ..., wn (with n components)",
+}
+
+// SpecOf returns the specification of distribution d
+func SpecOf(d Mapper) (Spec, error) {
+	switch t := d.(type) {
+	case *Uniform:
+		return Spec{Kind: "uniform"}, nil
+	case *JohnsonB:
+		if t.m > 0. {
+			return Spec{Kind: "johnsonb", Params: []float64{t.a1, t.a2, t.m}}, nil
+		}
+		return Spec{Kind: "johnsonb", Params: []float64{t.a1, t.a2}}, nil
+	case *Trapezoid:
+		if t.m == t.n && t.a == 2. && t.b == 2. {
+			return Spec{Kind: "triangle", Params: []float64{t.m}}, nil
+		}
+		return Spec{Kind: "trapezoid", Params: []float64{t.m, t.n, t.a, t.b}}, nil
+	case *Triangle:
+		return Spec{Kind: "triangle", Params: []float64{t.m}}, nil
+	case *Normal:
+		return Spec{Kind: "normal", Params: []float64{t.mu, t.sigma}}, nil
+	case *StudentT:
+		return Spec{Kind: "studentt", Params: []float64{t.nu, t.mu, t.sigma}}, nil
+	case *TruncatedNormal:
+		return Spec{Kind: "truncnormal", Params: []float64{t.mu, t.sigma, t.low, t.high}}, nil
+	case *LogNormal:
+		return Spec{Kind: "lognormal", Params: []float64{t.mu, t.sigma}}, nil
+	case *TruncatedLogNormal:
+		return Spec{Kind: "trunclognormal", Params: []float64{t.mu, t.sigma, t.low, t.high}}, nil
+	case *Beta:
+		return Spec{Kind: "beta", Params: []float64{t.a, t.b}}, nil
+	case *Gamma:
+		return Spec{Kind: "gamma", Params: []float64{t.k, t.theta}}, nil
+	case *Weibull:
+		return Spec{Kind: "weibull", Params: []float64{t.k, t.lambda}}, nil
+	case *Gumbel:
+		return Spec{Kind: "gumbel", Params: []float64{t.mu, t.beta}}, nil
+	case *GEV:
+		return Spec{Kind: "gev", Params: []float64{t.mu, t.sigma, t.xi}}, nil
+	case *Pearson3:
+		return Spec{Kind: "pearson3", Params: []float64{t.mean, t.sd, t.skew}}, nil
+	case *LogPearson3:
+		return Spec{Kind: "logpearson3", Params: []float64{t.p3.mean, t.p3.sd, t.p3.skew}}, nil
+	case *Johnson:
+		return Spec{Kind: "johnson", Params: []float64{float64(t.family), t.gamma, t.delta, t.xi, t.lambda}}, nil
+	case *PiecewiseLinear:
+		return Spec{Kind: "piecewise", Params: append(append([]float64{}, t.x...), t.f...)}, nil
+	case *KernelDensity:
+		return Spec{Kind: "kernel", Params: append([]float64{t.h}, t.x...)}, nil
+	case *DiscreteUniform:
+		return Spec{Kind: "discreteuniform", Params: []float64{float64(t.lo), float64(t.hi)}}, nil
+	case *Poisson:
+		return Spec{Kind: "poisson", Params: []float64{t.lambda}}, nil
+	case *Binomial:
+		return Spec{Kind: "binomial", Params: []float64{float64(t.n), t.p}}, nil
+	case *Categorical:
+		return Spec{Kind: "categorical", Params: t.Probabilities()}, nil
+	case *Mixture:
+		s := Spec{Kind: "mixture", Params: t.Weights(), Components: make([]Spec, len(t.comps))}
+		for i, c := range t.comps {
+			cs, err := SpecOf(c)
+			if err != nil {
+				return Spec{}, err
+			}
+			s.Components[i] = cs
+		}
+		return s, nil
+	}
+	return Spec{}, fmt.Errorf("invdistr.SpecOf: unsupported distribution type %T", d)
+}
+
+// Mapper returns the distribution specified
+func (s Spec) Mapper() (Mapper, error) {
+	p := s.Params
+	nparam := func(n int) error {
+		if len(p) != n {
+			return fmt.Errorf("invdistr.Spec: %s distribution requires %d parameters (%s), %d given: %w", s.Kind, n, Kinds[s.Kind], len(p), ErrDimensionMismatch)
+		}
+		return nil
+	}
+	switch s.Kind {
+	case "uniform":
+		return &Uniform{}, nil
+	case "johnsonb":
+		if len(p) != 3 {
+			if err := nparam(2); err != nil {
+				return nil, err
+			}
+		}
+		j, err := NewJohnsonBShape(p[0], p[1])
+		if err != nil || len(p) == 2 {
+			return j, err
+		}
+		if p[2] <= 0. || p[2] >= 1. {
+			return nil, fmt.Errorf("invdistr.Spec: johnsonb mode m = %v outside (0,1): %w", p[2], ErrInvalidShape)
+		}
+		j.m = p[2]
+		return j, nil
+	case "trapezoid":
+		if err := nparam(4); err != nil {
+			return nil, err
+		}
+		return NewTrapezoidE(p[0], p[1], p[2], p[3])
+	case "triangle":
+		if err := nparam(1); err != nil {
+			return nil, err
+		}
+		return NewTriangleE(p[0])
+	case "normal":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewNormal(p[0], p[1])
+	case "studentt":
+		if err := nparam(3); err != nil {
+			return nil, err
+		}
+		return NewStudentT(p[0], p[1], p[2])
+	case "truncnormal":
+		if err := nparam(4); err != nil {
+			return nil, err
+		}
+		return NewTruncatedNormal(p[0], p[1], p[2], p[3])
+	case "lognormal":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewLogNormal(p[0], p[1])
+	case "trunclognormal":
+		if err := nparam(4); err != nil {
+			return nil, err
+		}
+		return NewTruncatedLogNormal(p[0], p[1], p[2], p[3])
+	case "beta":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewBeta(p[0], p[1])
+	case "pert":
+		if err := nparam(3); err != nil {
+			return nil, err
+		}
+		return NewPERT(p[0], p[1], p[2])
+	case "gamma":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewGamma(p[0], p[1])
+	case "weibull":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewWeibull(p[0], p[1])
+	case "gumbel":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewGumbel(p[0], p[1])
+	case "gev":
+		if err := nparam(3); err != nil {
+			return nil, err
+		}
+		return NewGEV(p[0], p[1], p[2])
+	case "pearson3":
+		if err := nparam(3); err != nil {
+			return nil, err
+		}
+		return NewPearson3Moments(p[0], p[1], p[2])
+	case "logpearson3":
+		if err := nparam(3); err != nil {
+			return nil, err
+		}
+		return NewLogPearson3Moments(p[0], p[1], p[2])
+	case "johnson":
+		if err := nparam(5); err != nil {
+			return nil, err
+		}
+		return NewJohnson(JohnsonFamily(p[0]), p[1], p[2], p[3], p[4])
+	case "piecewise":
+		if len(p)%2 != 0 {
+			return nil, fmt.Errorf("invdistr.Spec: piecewise distribution requires an equal number of breakpoints and probabilities, %d parameters given: %w", len(p), ErrDimensionMismatch)
+		}
+		return NewPiecewiseLinear(p[:len(p)/2], p[len(p)/2:])
+	case "kernel":
+		if len(p) < 3 {
+			return nil, fmt.Errorf("invdistr.Spec: kernel distribution requires a bandwidth and at least 2 data, %d parameters given: %w", len(p), ErrDimensionMismatch)
+		}
+		return NewKernelDensity(p[1:], p[0])
+	case "discreteuniform":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewDiscreteUniform(int(p[0]), int(p[1]))
+	case "poisson":
+		if err := nparam(1); err != nil {
+			return nil, err
+		}
+		return NewPoisson(p[0])
+	case "binomial":
+		if err := nparam(2); err != nil {
+			return nil, err
+		}
+		return NewBinomial(int(p[0]), p[1])
+	case "categorical":
+		return NewCategorical(p)
+	case "mixture":
+		if err := nparam(len(s.Components)); err != nil {
+			return nil, err
+		}
+		comps := make([]Distribution, len(s.Components))
+		for i, c := range s.Components {
+			m, err := c.Mapper()
+			if err != nil {
+				return nil, err
+			}
+			d, ok := m.(Distribution)
+			if !ok {
+				return nil, fmt.Errorf("invdistr.Spec: mixture component %d (%s) has no CDF", i, c.Kind)
+			}
+			comps[i] = d
+		}
+		return NewMixture(p, comps...)
+	}
+	return nil, fmt.Errorf("invdistr.Spec: unknown distribution kind '%s'", s.Kind)
+}
+
+// mapSpec is the serializable form of a Map
+type mapSpec struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Log   bool    `json:"log,omitempty"`
+	Distr Spec    `json:"distribution"`
+}
+
+func (m *Map) spec() (mapSpec, error) {
+	s, err := SpecOf(m.Distr)
+	return mapSpec{Low: m.Low, High: m.High, Log: m.Log, Distr: s}, err
+}
+
+func (m *Map) set(s mapSpec) error {
+	d, err := s.Distr.Mapper()
+	if err != nil {
+		return err
+	}
+	*m = Map{Low: s.Low, High: s.High, Log: s.Log, Distr: d}
+	return nil
+}
+
+// MarshalJSON encodes the Map and its distribution specification
+func (m *Map) MarshalJSON() ([]byte, error) {
+	s, err := m.spec()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes the Map, rebuilding its distribution
+func (m *Map) UnmarshalJSON(b []byte) error {
+	var s mapSpec
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return m.set(s)
+}
+
+// GobEncode encodes the Map and its distribution specification
+func (m *Map) GobEncode() ([]byte, error) {
+	s, err := m.spec()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(s); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// GobDecode decodes the Map, rebuilding its distribution
+func (m *Map) GobDecode(b []byte) error {
+	var s mapSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+		return err
+	}
+	return m.set(s)
+}
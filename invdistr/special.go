@@ -0,0 +1,489 @@
+package invdistr
+
+import "math"
+
+// normCDF returns the standard normal cumulative distribution function
+func normCDF(z float64) float64 {
+	return .5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// normPDF returns the standard normal probability density function
+func normPDF(z float64) float64 {
+	return math.Exp(-.5*z*z) / math.Sqrt(2.*math.Pi)
+}
+
+// normQuantile returns the inverse of the standard normal cumulative distribution function,
+// accurate to about 1e-16 (relative) down to the smallest representable p
+// see Wichura, M.J., 1988. Algorithm AS 241: The Percentage Points of the Normal Distribution. Applied Statistics 37(3). pp.477-484.
+func normQuantile(p float64) float64 {
+	switch {
+	case math.IsNaN(p) || p < 0. || p > 1.:
+		return math.NaN()
+	case p == 0.:
+		return math.Inf(-1)
+	case p == 1.:
+		return math.Inf(1)
+	}
+	poly := func(c [8]float64, x float64) float64 {
+		y := c[7]
+		for i := 6; i >= 0; i-- {
+			y = y*x + c[i]
+		}
+		return y
+	}
+	q := p - .5
+	if math.Abs(q) <= .425 {
+		r := .180625 - q*q
+		return q * poly(as241a, r) / poly(as241b, r)
+	}
+	r := math.Sqrt(-math.Log(math.Min(p, 1.-p)))
+	var z float64
+	if r <= 5. {
+		r -= 1.6
+		z = poly(as241c, r) / poly(as241d, r)
+	} else {
+		r -= 5.
+		z = poly(as241e, r) / poly(as241f, r)
+	}
+	if q < 0. {
+		return -z
+	}
+	return z
+}
+
+// coefficients of AS 241 (PPND16), in increasing powers
+var (
+	as241a = [8]float64{3.387132872796366608, 133.14166789178437745, 1971.5909503065514427, 13731.693765509461125,
+		45921.953931549871457, 67265.770927008700853, 33430.575583588128105, 2509.0809287301226727}
+	as241b = [8]float64{1., 42.313330701600911252, 687.1870074920579083, 5394.1960214247511077,
+		21213.794301586595867, 39307.89580009271061, 28729.085735721942674, 5226.495278852545925}
+	as241c = [8]float64{1.42343711074968357734, 4.6303378461565452959, 5.7694972214606914055, 3.64784832476320460504,
+		1.27045825245236838258, .24178072517745061177, .0227238449892691845833, 7.7454501427834140764e-4}
+	as241d = [8]float64{1., 2.05319162663775882187, 1.6763848301838038494, .68976733498510000455,
+		.14810397642748007459, .0151986665636164571966, 5.475938084995344946e-4, 1.05075007164441684324e-9}
+	as241e = [8]float64{6.6579046435011037772, 5.4637849111641143699, 1.7848265399172913358, .29656057182850489123,
+		.026532189526576123093, .0012426609473880784386, 2.71155556874348757815e-5, 2.01033439929228813265e-7}
+	as241f = [8]float64{1., .59983220655588793769, .13692988092273580531, .0148753612908506148525,
+		7.868691311456132591e-4, 1.8463183175100546818e-5, 1.4215117583164458887e-7, 2.04426310338993978564e-15}
+)
+
+// normTruncQuantile returns the standard normal quantile of u[0,1] truncated to [a,b],
+// evaluated in the upper tail when the interval lies above the mean to preserve precision
+func normTruncQuantile(a, b, u float64) float64 {
+	if a > 0. {
+		qa, qb := normCDF(-a), normCDF(-b)
+		return -normQuantile(qa - u*(qa-qb))
+	}
+	pa, pb := normCDF(a), normCDF(b)
+	return normQuantile(pa + u*(pb-pa))
+}
+
+// lnBeta returns the natural logarithm of the beta function B(a,b)
+func lnBeta(a, b float64) float64 {
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	return la + lb - lab
+}
+
+// incBeta returns the regularized incomplete beta function I_x(a,b)
+// see pg. 270 of: Press, W.H., S.A. Teukolsky, W.T. Vetterling, B.P. Flannery, 2007. Numerical Recipes, third ed. Cambridge University Press. 1235pp.
+func incBeta(a, b, x float64) float64 {
+	if x <= 0. {
+		return 0.
+	}
+	if x >= 1. {
+		return 1.
+	}
+	bt := math.Exp(a*math.Log(x) + b*math.Log1p(-x) - lnBeta(a, b))
+	if x < (a+1.)/(a+b+2.) {
+		return bt * betacf(a, b, x) / a
+	}
+	return 1. - bt*betacf(b, a, 1.-x)/b
+}
+
+// betacf continued fraction evaluation for incBeta (modified Lentz's method)
+func betacf(a, b, x float64) float64 {
+	const tiny = 1e-300
+	qab, qap, qam := a+b, a+1., a-1.
+	c, d := 1., 1.-qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1. / d
+	h := d
+	for m := 1; m <= 10000; m++ {
+		fm := float64(m)
+		m2 := 2. * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1. + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1. + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1. / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1. + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1. + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1. / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1.) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
+
+// incBetaInv returns x such that I_x(a,b) = p, using Halley's method
+// from an initial approximation, polished by bisection should it fail to converge
+// see pg. 273 of: Press, W.H., S.A. Teukolsky, W.T. Vetterling, B.P. Flannery, 2007. Numerical Recipes, third ed. Cambridge University Press. 1235pp.
+func incBetaInv(a, b, p float64) float64 {
+	if p <= 0. {
+		return 0.
+	}
+	if p >= 1. {
+		return 1.
+	}
+	a1, b1 := a-1., b-1.
+	var x, t, u, w float64
+	if a >= 1. && b >= 1. {
+		pp := p
+		if p >= .5 {
+			pp = 1. - p
+		}
+		t = math.Sqrt(-2. * math.Log(pp))
+		x = (2.30753+t*.27061)/(1.+t*(.99229+t*.04481)) - t
+		if p < .5 {
+			x = -x
+		}
+		al := (x*x - 3.) / 6.
+		h := 2. / (1./(2.*a-1.) + 1./(2.*b-1.))
+		w = x*math.Sqrt(al+h)/h - (1./(2.*b-1.)-1./(2.*a-1.))*(al+5./6.-2./(3.*h))
+		x = a / (a + b*math.Exp(2.*w))
+	} else {
+		lna, lnb := math.Log(a/(a+b)), math.Log(b/(a+b))
+		t = math.Exp(a*lna) / a
+		u = math.Exp(b*lnb) / b
+		w = t + u
+		if p < t/w {
+			x = math.Pow(a*w*p, 1./a)
+		} else {
+			x = 1. - math.Pow(b*w*(1.-p), 1./b)
+		}
+	}
+	afac := -lnBeta(a, b)
+	for j := 0; j < 20; j++ {
+		if x <= 0. || x >= 1. {
+			break
+		}
+		e := incBeta(a, b, x) - p
+		t = math.Exp(a1*math.Log(x) + b1*math.Log1p(-x) + afac)
+		u = e / t
+		t = u / (1. - .5*math.Min(1., u*(a1/x-b1/(1.-x))))
+		x -= t
+		if x <= 0. {
+			x = .5 * (x + t)
+		}
+		if x >= 1. {
+			x = .5 * (x + t + 1.)
+		}
+		if math.Abs(t) < 1e-14*x && j > 0 {
+			break
+		}
+	}
+	if x > 0. && x < 1. && math.Abs(incBeta(a, b, x)-p) < 1e-10 {
+		return x
+	}
+	return bisect(func(x float64) float64 { return incBeta(a, b, x) - p }, 0., 1.)
+}
+
+// incGamma returns the regularized lower incomplete gamma function P(a,x)
+func incGamma(a, x float64) float64 {
+	p, _ := incGammaPQ(a, x)
+	return p
+}
+
+// incGammaQ returns the regularized upper incomplete gamma function Q(a,x) = 1 - P(a,x),
+// without loss of precision in the upper tail
+func incGammaQ(a, x float64) float64 {
+	_, q := incGammaPQ(a, x)
+	return q
+}
+
+// incGammaPQ returns the regularized lower and upper incomplete gamma functions P(a,x) and Q(a,x)
+// see pg. 259 of: Press, W.H., S.A. Teukolsky, W.T. Vetterling, B.P. Flannery, 2007. Numerical Recipes, third ed. Cambridge University Press. 1235pp.
+func incGammaPQ(a, x float64) (float64, float64) {
+	if x <= 0. {
+		return 0., 1.
+	}
+	if a >= 100. {
+		return incGammaQuad(a, x)
+	}
+	gln, _ := math.Lgamma(a)
+	if x < a+1. { // series representation
+		ap, del := a, 1./a
+		sum := del
+		for n := 0; n < 10000; n++ {
+			ap++
+			del *= x / ap
+			sum += del
+			if math.Abs(del) < math.Abs(sum)*1e-16 {
+				break
+			}
+		}
+		p := sum * math.Exp(-x+a*math.Log(x)-gln)
+		return p, 1. - p
+	}
+	// continued fraction representation (modified Lentz's method)
+	const tiny = 1e-300
+	b := x + 1. - a
+	c, d := 1./tiny, 1./b
+	h := d
+	for i := 1; i < 10000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2.
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1. / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1.) < 1e-16 {
+			break
+		}
+	}
+	q := math.Exp(-x+a*math.Log(x)-gln) * h
+	return 1. - q, q
+}
+
+// Gauss-Legendre abscissas and weights used in incGammaQuad
+var (
+	glY = [18]float64{0.0021695375159141994, 0.011413521097787704, 0.027972308950302116, 0.051727015600492421, 0.082502225484340941, 0.12007019910960293,
+		0.16415283300752470, 0.21442376986779355, 0.27051082840644336, 0.33199876341447887, 0.39843234186401943, 0.46931971407375483,
+		0.54413605556657973, 0.62232745288031077, 0.70331500465597174, 0.78649910768313447, 0.87126389619061517, 0.95698180152629142}
+	glW = [18]float64{0.0055657196642445571, 0.012915947284065419, 0.020181515297735382, 0.027298621498568734, 0.034213810770299537, 0.040875750923643261,
+		0.047235083490265582, 0.053244713977759692, 0.058860144245324798, 0.064039797355015485, 0.068745323835736408, 0.072941885005653087,
+		0.076598410645870640, 0.079687828912071670, 0.082187266704339706, 0.084078218979661945, 0.085346685739338721, 0.085983275670394821}
+)
+
+// incGammaQuad returns P(a,x) and Q(a,x) for large a by Gauss-Legendre quadrature
+func incGammaQuad(a, x float64) (float64, float64) {
+	a1 := a - 1.
+	lna1, sqrta1 := math.Log(a1), math.Sqrt(a1)
+	gln, _ := math.Lgamma(a)
+	var xu float64
+	if x > a1 {
+		xu = math.Max(a1+11.5*sqrta1, x+6.*sqrta1)
+	} else {
+		xu = math.Max(0., math.Min(a1-7.5*sqrta1, x-5.*sqrta1))
+	}
+	sum := 0.
+	for j := range glY {
+		t := x + (xu-x)*glY[j]
+		sum += glW[j] * math.Exp(-(t-a1)+a1*(math.Log(t)-lna1))
+	}
+	ans := sum * (xu - x) * math.Exp(a1*(lna1-1.)-gln)
+	if ans > 0. {
+		return 1. - ans, ans
+	}
+	return -ans, 1. + ans
+}
+
+// incGammaInv returns x such that P(a,x) = p, using Halley's method
+// from an initial approximation, polished by bisection should it fail to converge.
+// The residual is evaluated from Q(a,x) in the upper tail (p > .5) to preserve precision.
+// see pg. 263 of: Press, W.H., S.A. Teukolsky, W.T. Vetterling, B.P. Flannery, 2007. Numerical Recipes, third ed. Cambridge University Press. 1235pp.
+func incGammaInv(a, p float64) float64 {
+	if p <= 0. {
+		return 0.
+	}
+	if p >= 1. {
+		return math.Inf(1)
+	}
+	a1 := a - 1.
+	gln, _ := math.Lgamma(a)
+	var x, t, lna1, afac float64
+	if a > 1. {
+		lna1 = math.Log(a1)
+		afac = math.Exp(a1*(lna1-1.) - gln)
+		pp := p
+		if p >= .5 {
+			pp = 1. - p
+		}
+		t = math.Sqrt(-2. * math.Log(pp))
+		x = (2.30753+t*.27061)/(1.+t*(.99229+t*.04481)) - t
+		if p < .5 {
+			x = -x
+		}
+		x = math.Max(1e-3, a*math.Pow(1.-1./(9.*a)-x/(3.*math.Sqrt(a)), 3.))
+	} else {
+		t = 1. - a*(.253+a*.12)
+		if p < t {
+			x = math.Pow(p/t, 1./a)
+		} else {
+			x = 1. - math.Log(1.-(p-t)/(1.-t))
+		}
+	}
+	resid := func(x float64) float64 { return incGamma(a, x) - p }
+	if p > .5 {
+		q := 1. - p
+		resid = func(x float64) float64 { return q - incGammaQ(a, x) }
+	}
+	for j := 0; j < 20; j++ {
+		if x <= 0. {
+			break
+		}
+		e := resid(x)
+		if a > 1. {
+			t = afac * math.Exp(-(x-a1)+a1*(math.Log(x)-lna1))
+		} else {
+			t = math.Exp(-x + a1*math.Log(x) - gln)
+		}
+		u := e / t
+		t = u / (1. - .5*math.Min(1., u*((a-1.)/x-1.)))
+		x -= t
+		if x <= 0. {
+			x = .5 * (x + t)
+		}
+		if math.Abs(t) < 1e-14*x {
+			break
+		}
+	}
+	if x > 0. && math.Abs(resid(x)) < 1e-10*math.Min(p, 1.-p) {
+		return x
+	}
+	hi := math.Max(1., a)
+	for resid(hi) < 0. {
+		hi *= 2.
+	}
+	return bisect(resid, 0., hi)
+}
+
+// bisect returns the root of monotonically increasing function f within [lo, hi]
+func bisect(f func(float64) float64, lo, hi float64) float64 {
+	for i := 0; i < 200; i++ {
+		m := (lo + hi) / 2.
+		if m == lo || m == hi {
+			break
+		}
+		if f(m) < 0. {
+			lo = m
+		} else {
+			hi = m
+		}
+	}
+	return (lo + hi) / 2.
+}
+
+// digamma returns the logarithmic derivative of the gamma function
+func digamma(x float64) float64 {
+	r := 0.
+	for ; x < 6.; x++ {
+		r -= 1. / x
+	}
+	x2 := 1. / x / x
+	return r + math.Log(x) - .5/x - x2*(1./12.-x2*(1./120.-x2/252.))
+}
+
+// trigamma returns the derivative of the digamma function
+func trigamma(x float64) float64 {
+	r := 0.
+	for ; x < 6.; x++ {
+		r += 1. / x / x
+	}
+	x2 := 1. / x / x
+	return r + 1./x + x2/2. + x2/x*(1./6.-x2*(1./30.-x2*(1./42.-x2/30.)))
+}
+
+// nelderMead returns the minimum of f using the Nelder-Mead downhill simplex method,
+// starting from x0 with initial simplex step sizes dx
+func nelderMead(f func([]float64) float64, x0, dx []float64) []float64 {
+	const (
+		maxit = 5000
+		ftol  = 1e-12
+	)
+	n := len(x0)
+	s, fs := make([][]float64, n+1), make([]float64, n+1)
+	for i := range s {
+		s[i] = make([]float64, n)
+		copy(s[i], x0)
+		if i > 0 {
+			s[i][i-1] += dx[i-1]
+		}
+		fs[i] = f(s[i])
+	}
+	at := func(c []float64, x []float64, a float64) []float64 { // c + a(x-c)
+		p := make([]float64, n)
+		for j := range p {
+			p[j] = c[j] + a*(x[j]-c[j])
+		}
+		return p
+	}
+	for it := 0; it < maxit; it++ {
+		// order the simplex: best first, worst last
+		for i := 1; i <= n; i++ {
+			for j := i; j > 0 && fs[j] < fs[j-1]; j-- {
+				s[j], s[j-1] = s[j-1], s[j]
+				fs[j], fs[j-1] = fs[j-1], fs[j]
+			}
+		}
+		if math.Abs(fs[n]-fs[0]) <= ftol*(math.Abs(fs[0])+math.Abs(fs[n])+1e-300) {
+			break
+		}
+		c := make([]float64, n) // centroid of all but worst
+		for i := 0; i < n; i++ {
+			for j := range c {
+				c[j] += s[i][j] / float64(n)
+			}
+		}
+		xr := at(c, s[n], -1.)
+		fr := f(xr)
+		switch {
+		case fr < fs[0]:
+			xe := at(c, s[n], -2.)
+			if fe := f(xe); fe < fr {
+				s[n], fs[n] = xe, fe
+			} else {
+				s[n], fs[n] = xr, fr
+			}
+		case fr < fs[n-1]:
+			s[n], fs[n] = xr, fr
+		default:
+			xc := at(c, s[n], .5)
+			if fr < fs[n] {
+				xc = at(c, xr, .5)
+			}
+			if fc := f(xc); fc < math.Min(fr, fs[n]) {
+				s[n], fs[n] = xc, fc
+				continue
+			}
+			for i := 1; i <= n; i++ { // shrink toward best
+				s[i] = at(s[0], s[i], .5)
+				fs[i] = f(s[i])
+			}
+		}
+	}
+	ib := 0
+	for i := range fs {
+		if fs[i] < fs[ib] {
+			ib = i
+		}
+	}
+	return s[ib]
+}
@@ -5,6 +5,7 @@
 package invdistr
 
 import (
+	"fmt"
 	"log"
 	"math"
 )
@@ -17,37 +18,107 @@ type Trapezoid struct {
 
 // NewTrapezoid constructor
 func NewTrapezoid(m, n, a, b float64) *Trapezoid {
-	if m < 0. || m > n || n > 1. || a < 1. || b < 1. {
-		log.Panicf("invdistr.NewTrapezoid: General Trapezoid distribution: invalid arguments m, n, a, b = %v, %v, %v, %v\n", m, n, a, b)
+	t, err := NewTrapezoidE(m, n, a, b)
+	if err != nil {
+		log.Panicln(err)
+	}
+	return t
+}
+
+// NewTrapezoidE constructor with modes 0 <= m <= n <= 1 and shape factors a, b >= 1,
+// returning an error for invalid arguments
+func NewTrapezoidE(m, n, a, b float64) (*Trapezoid, error) {
+	if !(m >= 0. && m <= n && n <= 1. && a >= 1. && b >= 1.) {
+		return nil, fmt.Errorf("invdistr.NewTrapezoid: invalid arguments m, n, a, b = %v, %v, %v, %v: %w", m, n, a, b, ErrInvalidShape)
 	}
 	t := new(Trapezoid)
 	t.m = m
 	t.n = n
 	t.a = a
 	t.b = b
-	return t
+	return t, nil
 }
 
-// Inv : inverse function
+// Inv : inverse function, returning NaN for invalid arguments (only possible for
+// a Trapezoid not built by its constructors) or u outside [0,1]
 func (t *Trapezoid) Inv(u float64) float64 {
 	m, n, a, b := t.properties()
-	if m < 0. || m > n || n > 1. || a < 1. || b < 1. {
-		panic("Trapezoid.Inv: Inverse General Trapezoid: invalid arguments")
+	if !(m >= 0. && m <= n && n <= 1. && a >= 1. && b >= 1.) || !(u >= 0. && u <= 1.) {
+		return math.NaN()
 	}
-	pd := b*m + a*b*(n-m) + a*(1.-n)
-	p1 := b * m / pd
-	p2 := a * b * (n - m) / pd
-	p3 := a * (1. - n) / pd
-	if u <= p1 {
+	p1, p2, p3 := t.probabilities()
+	if u <= p1 && p1 > 0. {
 		return m * math.Pow(u/p1, 1./a)
-	} else if u <= 1.-p3 {
+	} else if u <= 1.-p3 && p2 > 0. {
 		return u*(n-m)/p2 + m*(1.-1./a)
-	} else if u > 1.-p3 {
-		return 1. - (1.-n)*math.Pow((1.-u)/p3, 1./b)
 	}
-	panic("Trapezoid.Inv: error in Inverse General Trapezoid")
+	return 1. - (1.-n)*math.Pow((1.-u)/p3, 1./b)
 }
 
 func (t *Trapezoid) properties() (float64, float64, float64, float64) {
 	return t.m, t.n, t.a, t.b
 }
+
+// probabilities returns the probability mass of the lower, central and upper segments
+func (t *Trapezoid) probabilities() (float64, float64, float64) {
+	m, n, a, b := t.properties()
+	pd := b*m + a*b*(n-m) + a*(1.-n)
+	return b * m / pd, a * b * (n - m) / pd, a * (1. - n) / pd
+}
+
+// PDF : probability density function
+func (t *Trapezoid) PDF(x float64) float64 {
+	m, n, a, b := t.properties()
+	p1, p2, p3 := t.probabilities()
+	switch {
+	case x < 0. || x > 1.:
+		return 0.
+	case x < m:
+		return p1 * a * math.Pow(x/m, a-1.) / m
+	case x > n:
+		return p3 * b * math.Pow((1.-x)/(1.-n), b-1.) / (1. - n)
+	case n > m:
+		return p2 / (n - m)
+	case m > 0.: // x = m = n
+		return p1 * a / m
+	}
+	return p3 * b / (1. - n)
+}
+
+// CDF : cumulative distribution function
+func (t *Trapezoid) CDF(x float64) float64 {
+	m, n, a, b := t.properties()
+	p1, p2, p3 := t.probabilities()
+	switch {
+	case x <= 0.:
+		return 0.
+	case x >= 1.:
+		return 1.
+	case x < m:
+		return p1 * math.Pow(x/m, a)
+	case x > n:
+		return 1. - p3*math.Pow((1.-x)/(1.-n), b)
+	case n > m:
+		return p1 + p2*(x-m)/(n-m)
+	}
+	return p1
+}
+
+// Mean of the distribution
+func (t *Trapezoid) Mean() float64 {
+	m, n, a, b := t.properties()
+	p1, p2, p3 := t.probabilities()
+	return p1*a*m/(a+1.) + p2*(m+n)/2. + p3*(1.-b*(1.-n)/(b+1.))
+}
+
+// Variance of the distribution
+func (t *Trapezoid) Variance() float64 {
+	m, n, a, b := t.properties()
+	p1, p2, p3 := t.probabilities()
+	mn, d := t.Mean(), 1.-n
+	m2 := p1*a*m*m/(a+2.) + p2*(m*m+m*n+n*n)/3. + p3*(1.-2.*b*d/(b+1.)+b*d*d/(b+2.))
+	return m2 - mn*mn
+}
+
+// Support returns the range of the distribution
+func (t *Trapezoid) Support() (float64, float64) { return 0., 1. }
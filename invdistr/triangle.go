@@ -4,7 +4,11 @@
 
 package invdistr
 
-import "log"
+import (
+	"fmt"
+	"log"
+	"math"
+)
 
 // Triangle sampling distribution
 type Triangle struct {
@@ -15,19 +19,46 @@ type Triangle struct {
 // Returns a Triangular probability distribution
 // from u[0,1] with mode m. (special case: m=n, a=2, b=2: Triangular transform)
 func NewTriangle(m float64) *Trapezoid {
-	if m < 0. || m > 1. {
-		log.Panicf("Inverse General Triangle: invalid arguments m = %v\n", m)
+	t, err := NewTriangleE(m)
+	if err != nil {
+		log.Panicln(err)
+	}
+	return t
+}
+
+// NewTriangleE constructor with mode m [0,1], returning an error for invalid arguments
+func NewTriangleE(m float64) (*Trapezoid, error) {
+	if m < 0. || m > 1. || math.IsNaN(m) {
+		return nil, fmt.Errorf("invdistr.NewTriangle: invalid argument m = %v: %w", m, ErrInvalidShape)
 	}
 	t := new(Trapezoid)
 	t.m = m
 	t.n = m
 	t.a = 2.
 	t.b = 2.
-	return t
+	return t, nil
 }
 
 // Inv : inverse function
 func (t *Triangle) Inv(u float64) float64 {
-	trap := NewTrapezoid(t.m, t.m, 2., 2.)
-	return trap.Inv(u)
+	return (&Trapezoid{m: t.m, n: t.m, a: 2., b: 2.}).Inv(u)
+}
+
+// PDF : probability density function
+func (t *Triangle) PDF(x float64) float64 {
+	return (&Trapezoid{m: t.m, n: t.m, a: 2., b: 2.}).PDF(x)
+}
+
+// CDF : cumulative distribution function
+func (t *Triangle) CDF(x float64) float64 {
+	return (&Trapezoid{m: t.m, n: t.m, a: 2., b: 2.}).CDF(x)
 }
+
+// Mean of the distribution
+func (t *Triangle) Mean() float64 { return (t.m + 1.) / 3. }
+
+// Variance of the distribution
+func (t *Triangle) Variance() float64 { return (t.m*t.m - t.m + 1.) / 18. }
+
+// Support returns the range of the distribution
+func (t *Triangle) Support() (float64, float64) { return 0., 1. }
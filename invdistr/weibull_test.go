@@ -0,0 +1,30 @@
+package invdistr
+
+import (
+	"math"
+	"testing"
+)
+
+// TestWeibull checks Weibull quantiles, including small shape parameters and extreme u,
+// against F(x) = 1 - exp(-(x/λ)^k), i.e., x = λ(-ln(1-u))^(1/k)
+func TestWeibull(t *testing.T) {
+	for _, c := range []struct{ k, lambda float64 }{{.5, 1.}, {1., 2.}, {1.5, 10.}, {3.6, .5}} {
+		w, err := NewWeibull(c.k, c.lambda)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []float64{1e-10, 1e-4, .05, 1. - math.Exp(-1.), .95, 1. - 1e-4, 1. - 1e-10} {
+			want := c.lambda * math.Pow(-math.Log1p(-u), 1./c.k)
+			if x := w.Inv(u); relErr(x, want) > 1e-12 {
+				t.Errorf("Weibull(%v, %v).Inv(%v) = %v, want %v", c.k, c.lambda, u, x, want)
+			}
+			if p := w.CDF(want); relErr(p, u) > 1e-8 {
+				t.Errorf("Weibull(%v, %v).CDF(%v) = %v, want %v", c.k, c.lambda, want, p, u)
+			}
+		}
+		// the scale parameter is the 63.2th percentile
+		if x := w.Inv(1. - math.Exp(-1.)); relErr(x, c.lambda) > 1e-12 {
+			t.Errorf("Weibull(%v, %v).Inv(1-1/e) = %v, want %v", c.k, c.lambda, x, c.lambda)
+		}
+	}
+}
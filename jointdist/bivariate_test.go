@@ -0,0 +1,128 @@
+package jointdist
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"testing"
+)
+
+// kendall returns Kendall's tau of x and y (no ties)
+func kendall(x, y []float64) float64 {
+	s, n := 0., len(x)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if (x[i]-x[j])*(y[i]-y[j]) > 0. {
+				s++
+			} else {
+				s--
+			}
+		}
+	}
+	return 2. * s / float64(n) / float64(n-1)
+}
+
+// TestBivariate checks Kendall's tau and the uniform marginals of seeded samples of each copula
+func TestBivariate(t *testing.T) {
+	const n = 5000
+	newT := func(nu float64) func(float64) (Bivariate, error) {
+		return func(tau float64) (Bivariate, error) { return NewStudentTTau(tau, nu) }
+	}
+	for _, c := range []struct {
+		name string
+		new  func(float64) (Bivariate, error)
+	}{
+		{"StudentT(nu=3)", newT(3.)},
+		{"StudentT(nu=30)", newT(30.)},
+		{"Clayton", func(tau float64) (Bivariate, error) { return NewClaytonTau(tau) }},
+		{"Gumbel", func(tau float64) (Bivariate, error) { return NewGumbelTau(tau) }},
+	} {
+		for _, tau := range []float64{.2, .5, .8} {
+			b, err := c.new(tau)
+			if err != nil {
+				t.Fatalf("%s tau = %v: %v", c.name, tau, err)
+			}
+			if math.Abs(b.Tau()-tau) > 1e-12 {
+				t.Errorf("%s tau = %v: Tau() = %v", c.name, tau, b.Tau())
+			}
+			u := uniforms(n, 2, 1)
+			v := ApplyPair(b, u, 0, 1)
+			for k := range v {
+				if v[k][0] != u[k][0] {
+					t.Fatalf("%s tau = %v: u1 changed at row %d", c.name, tau, k)
+				}
+				if v[k][1] < 0. || v[k][1] > 1. || math.IsNaN(v[k][1]) {
+					t.Fatalf("%s tau = %v: v2 = %v at row %d", c.name, tau, v[k][1], k)
+				}
+			}
+			x, y := column(v, 0), column(v, 1)
+			if got := kendall(x, y); math.Abs(got-tau) > .03 {
+				t.Errorf("%s: Kendall tau = %.4f, want %v", c.name, got, tau)
+			}
+			sort.Float64s(y)
+			for _, p := range []float64{.25, .5, .75} {
+				if q := y[int(p*n)]; math.Abs(q-p) > .04 {
+					t.Errorf("%s tau = %v: v2 quantile %v = %.4f", c.name, tau, p, q)
+				}
+			}
+		}
+	}
+}
+
+// TestBivariateTails checks the tail dependence coefficients
+func TestBivariateTails(t *testing.T) {
+	st, _ := NewStudentT(.5, 1.) // t with 2 degrees of freedom: λ = 2F(-√(2/3)) = 1/2
+	cl, _ := NewClayton(2.)
+	gu, _ := NewGumbel(2.)
+	for _, c := range []struct {
+		name         string
+		b            Bivariate
+		lower, upper float64
+	}{
+		{"StudentT", st, .5, .5},
+		{"Clayton", cl, math.Sqrt(.5), 0.},
+		{"Gumbel", gu, 0., 2. - math.Sqrt2},
+	} {
+		if l, u := c.b.LowerTail(), c.b.UpperTail(); math.Abs(l-c.lower) > 1e-4 || math.Abs(u-c.upper) > 1e-4 {
+			t.Errorf("%s: tails = %.4f, %.4f, want %.4f, %.4f", c.name, l, u, c.lower, c.upper)
+		}
+	}
+}
+
+// TestBivariateErrors checks that the constructors reject parameters out of range
+func TestBivariateErrors(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	for _, c := range []struct {
+		name string
+		f    func() error
+	}{
+		{"NewStudentT(1, 3)", func() error { _, err := NewStudentT(1., 3.); return err }},
+		{"NewStudentT(-1, 3)", func() error { _, err := NewStudentT(-1., 3.); return err }},
+		{"NewStudentT(NaN, 3)", func() error { _, err := NewStudentT(nan, 3.); return err }},
+		{"NewStudentT(.5, 0)", func() error { _, err := NewStudentT(.5, 0.); return err }},
+		{"NewStudentT(.5, Inf)", func() error { _, err := NewStudentT(.5, inf); return err }},
+		{"NewStudentTTau(1, 3)", func() error { _, err := NewStudentTTau(1., 3.); return err }},
+		{"NewStudentTTau(-1, 3)", func() error { _, err := NewStudentTTau(-1., 3.); return err }},
+		{"NewStudentTTau(.5, -1)", func() error { _, err := NewStudentTTau(.5, -1.); return err }},
+		{"NewClayton(0)", func() error { _, err := NewClayton(0.); return err }},
+		{"NewClayton(-1)", func() error { _, err := NewClayton(-1.); return err }},
+		{"NewClayton(Inf)", func() error { _, err := NewClayton(inf); return err }},
+		{"NewClayton(NaN)", func() error { _, err := NewClayton(nan); return err }},
+		{"NewClaytonTau(0)", func() error { _, err := NewClaytonTau(0.); return err }},
+		{"NewClaytonTau(1)", func() error { _, err := NewClaytonTau(1.); return err }},
+		{"NewClaytonTau(NaN)", func() error { _, err := NewClaytonTau(nan); return err }},
+		{"NewGumbel(.5)", func() error { _, err := NewGumbel(.5); return err }},
+		{"NewGumbel(Inf)", func() error { _, err := NewGumbel(inf); return err }},
+		{"NewGumbel(NaN)", func() error { _, err := NewGumbel(nan); return err }},
+		{"NewGumbelTau(-.1)", func() error { _, err := NewGumbelTau(-.1); return err }},
+		{"NewGumbelTau(1)", func() error { _, err := NewGumbelTau(1.); return err }},
+		{"NewGumbelTau(NaN)", func() error { _, err := NewGumbelTau(nan); return err }},
+	} {
+		if err := c.f(); !errors.Is(err, ErrInvalidShape) {
+			t.Errorf("%s: error %v", c.name, err)
+		}
+	}
+	if _, err := NewGumbelTau(0.); err != nil {
+		t.Errorf("NewGumbelTau(0) (independence): %v", err)
+	}
+}
@@ -4,6 +4,8 @@
 package jointdist
 
 import (
+	"fmt"
+	"log"
 	"math"
 	"math/rand"
 )
@@ -19,10 +21,19 @@ func Elliptical(u1, u2, spearmanRho float64) (float64, float64) {
 // DiagonalBand copula
 // see pg 39 in Kurowicka, D. and R. Cooke. Uncertainty Analysis with High Dimensional Dependence Modelling. John Wiley & Sons, Ltd. 2006. 284pp.
 func DiagonalBand(u1, u2, correlation float64) (float64, float64) {
+	v1, v2, err := DiagonalBandE(u1, u2, correlation)
+	if err != nil {
+		log.Panicln(err)
+	}
+	return v1, v2
+}
+
+// DiagonalBandE copula, returning an error for a correlation outside [-1,1]
+func DiagonalBandE(u1, u2, correlation float64) (float64, float64, error) {
 	acor := math.Abs(correlation)
 	neg := correlation < 0.0
-	if acor > 1. {
-		panic("DiagonalBand copula input error")
+	if acor > 1. || math.IsNaN(acor) {
+		return u1, u2, fmt.Errorf("DiagonalBand copula input error: correlation = %v: %w", correlation, ErrInvalidShape)
 	}
 
 	v1, v2 := u1, u2
@@ -36,7 +47,7 @@ func DiagonalBand(u1, u2, correlation float64) (float64, float64) {
 	} else {
 		u2 = 2.*(1.-acor)*v2 + v1 + acor - 1.
 	}
-	return u1, u2
+	return u1, u2, nil
 }
 
 // Franks copula
@@ -44,10 +55,19 @@ func DiagonalBand(u1, u2, correlation float64) (float64, float64) {
 // causion, u2 is not informed by the previous sampling plan, and thus low sampling dicsrepancy (i.e., LHC) will not be preserved
 // see pg 47 in Kurowicka, D. and R. Cooke. Uncertainty Analysis with High Dimensional Dependence Modelling. John Wiley & Sons, Ltd. 2006. 284pp.
 func Franks(u1, theta float64, rng *rand.Rand) (float64, float64) {
-	if theta == 0. {
-		panic("Franks copula input error")
+	v1, v2, err := FranksE(u1, theta, rng)
+	if err != nil {
+		log.Panicln(err)
+	}
+	return v1, v2
+}
+
+// FranksE copula, returning an error for theta = 0
+func FranksE(u1, theta float64, rng *rand.Rand) (float64, float64, error) {
+	if theta == 0. || math.IsNaN(theta) {
+		return u1, math.NaN(), fmt.Errorf("Franks copula input error: theta = %v: %w", theta, ErrInvalidShape)
 	}
 
 	u2 := -math.Log(1.-(1.-math.Exp(-theta))/((1./rng.Float64()-1.)*math.Exp(-theta*u1)+1.)) / theta
-	return u1, u2
+	return u1, u2, nil
 }
@@ -0,0 +1,9 @@
+package jointdist
+
+import "errors"
+
+// Errors returned (wrapped) by the copulae, test using errors.Is
+var (
+	ErrInvalidShape      = errors.New("invalid shape parameter")
+	ErrDimensionMismatch = errors.New("dimension mismatch")
+)
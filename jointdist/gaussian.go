@@ -0,0 +1,265 @@
+// gaussian.go a multivariate Gaussian (normal) copula: an entire U[0,1]^p sample is transformed to
+// standard normal scores, correlated by the Cholesky factor of a p×p (Pearson) correlation matrix, and
+// mapped back to U[0,1]^p. The first dimension is left unchanged, and every marginal remains U[0,1].
+// Rank (Spearman) correlations can be given instead, converted by r = 2sin(πρs/6).
+// Correlation matrices that are not positive definite (e.g., elicited pair by pair) are replaced by the
+// nearest correlation matrix, see: Higham, N.J., 2002. Computing the nearest correlation matrix - a problem
+// from finance. IMA Journal of Numerical Analysis 22. pp.329-343.
+// see Kurowicka, D. and R. Cooke. Uncertainty Analysis with High Dimensional Dependence Modelling. John Wiley & Sons, Ltd. 2006. 284pp.
+
+package jointdist
+
+import (
+	"fmt"
+	"math"
+)
+
+// GaussianCopula multivariate copula
+type GaussianCopula struct {
+	corr, l   [][]float64 // correlation matrix and its lower Cholesky factor
+	corrected bool
+}
+
+// NewGaussianCopula constructor from a p×p correlation matrix, of rank (Spearman) correlations
+// when spearman is true, otherwise (Pearson) correlations of the normal scores
+func NewGaussianCopula(corr [][]float64, spearman bool) (*GaussianCopula, error) {
+	p := len(corr)
+	if p == 0 {
+		return nil, fmt.Errorf("jointdist.NewGaussianCopula: empty correlation matrix: %w", ErrDimensionMismatch)
+	}
+	c := make([][]float64, p)
+	for i, r := range corr {
+		if len(r) != p {
+			return nil, fmt.Errorf("jointdist.NewGaussianCopula: correlation matrix row %d has %d columns, %d expected: %w", i, len(r), p, ErrDimensionMismatch)
+		}
+		c[i] = make([]float64, p)
+		for j, x := range r {
+			switch {
+			case math.IsNaN(x) || x < -1. || x > 1.:
+				return nil, fmt.Errorf("jointdist.NewGaussianCopula: invalid correlation [%d][%d] = %v: %w", i, j, x, ErrInvalidShape)
+			case i == j && x != 1.:
+				return nil, fmt.Errorf("jointdist.NewGaussianCopula: diagonal [%d][%d] = %v, must be 1: %w", i, j, x, ErrInvalidShape)
+			case math.Abs(x-corr[j][i]) > 1e-10:
+				return nil, fmt.Errorf("jointdist.NewGaussianCopula: correlation matrix not symmetric at [%d][%d]: %w", i, j, ErrInvalidShape)
+			}
+			if spearman {
+				x = SpearmanToPearson(x)
+			}
+			c[i][j] = x
+		}
+	}
+	g := &GaussianCopula{corr: c}
+	if g.l = cholesky(c); g.l == nil {
+		g.corr, g.corrected = NearestCorrelation(c), true
+		if g.l = cholesky(g.corr); g.l == nil {
+			return nil, fmt.Errorf("jointdist.NewGaussianCopula: correlation matrix could not be made positive definite: %w", ErrInvalidShape)
+		}
+	}
+	return g, nil
+}
+
+// SpearmanToPearson converts a rank (Spearman) correlation to the (Pearson) correlation of normal scores
+func SpearmanToPearson(rho float64) float64 { return 2. * math.Sin(math.Pi*rho/6.) }
+
+// PearsonToSpearman converts a (Pearson) correlation of normal scores to a rank (Spearman) correlation
+func PearsonToSpearman(r float64) float64 { return 6. / math.Pi * math.Asin(r/2.) }
+
+// Correlation returns the (Pearson) correlation matrix used, after any correction
+func (g *GaussianCopula) Correlation() [][]float64 { return copyMatrix(g.corr) }
+
+// Corrected returns true if the correlation matrix given was replaced by the nearest positive definite one
+func (g *GaussianCopula) Corrected() bool { return g.corrected }
+
+// Ndim returns the dimension of the copula
+func (g *GaussianCopula) Ndim() int { return len(g.corr) }
+
+// Transform returns the correlated sample of U[0,1]^p sample u
+func (g *GaussianCopula) Transform(u []float64) ([]float64, error) {
+	p := len(g.l)
+	if len(u) != p {
+		return nil, fmt.Errorf("GaussianCopula.Transform: %d values given for %d dimensions: %w", len(u), p, ErrDimensionMismatch)
+	}
+	z := make([]float64, p)
+	for i, v := range u {
+		z[i] = math.Sqrt2 * math.Erfinv(2.*math.Min(math.Max(v, 1e-16), 1.-1e-16)-1.)
+	}
+	o := make([]float64, p)
+	for i := 0; i < p; i++ {
+		x := 0.
+		for j := 0; j <= i; j++ {
+			x += g.l[i][j] * z[j]
+		}
+		o[i] = .5 * math.Erfc(-x/math.Sqrt2)
+	}
+	return o, nil
+}
+
+// TransformAll returns the correlated samples of every row of u, e.g., LatinHyperCube.UT()
+func (g *GaussianCopula) TransformAll(u [][]float64) ([][]float64, error) {
+	o := make([][]float64, len(u))
+	for k, r := range u {
+		v, err := g.Transform(r)
+		if err != nil {
+			return nil, fmt.Errorf("GaussianCopula.TransformAll: row %d: %w", k, err)
+		}
+		o[k] = v
+	}
+	return o, nil
+}
+
+// cholesky returns the lower triangular factor L of a = LLᵀ, nil if a is not positive definite
+func cholesky(a [][]float64) [][]float64 {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			s := a[i][j]
+			for k := 0; k < j; k++ {
+				s -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if s <= 1e-12 {
+					return nil
+				}
+				l[i][i] = math.Sqrt(s)
+			} else {
+				l[i][j] = s / l[j][j]
+			}
+		}
+	}
+	return l
+}
+
+// NearestCorrelation returns the nearest (Frobenius norm) positive definite correlation matrix to
+// symmetric matrix a, by the alternating projections method of Higham (2002) with Dykstra's correction
+func NearestCorrelation(a [][]float64) [][]float64 {
+	const (
+		eps   = 1e-8 // minimum eigenvalue retained, such that the result is positive definite
+		tol   = 1e-10
+		maxit = 1000
+	)
+	n := len(a)
+	y, ds := copyMatrix(a), make([][]float64, n)
+	for i := range ds {
+		ds[i] = make([]float64, n)
+	}
+	for it := 0; it < maxit; it++ {
+		r := copyMatrix(y) // projection onto the positive semi-definite matrices
+		for i := range r {
+			for j := range r {
+				r[i][j] -= ds[i][j]
+			}
+		}
+		x := psdProject(r, eps)
+		for i := range ds {
+			for j := range ds {
+				ds[i][j] = x[i][j] - r[i][j]
+			}
+		}
+		d := 0. // projection onto the unit-diagonal matrices
+		for i := range x {
+			for j := range x {
+				yn := x[i][j]
+				if i == j {
+					yn = 1.
+				}
+				d = math.Max(d, math.Abs(yn-y[i][j]))
+				y[i][j] = yn
+			}
+		}
+		if d < tol {
+			break
+		}
+	}
+	x := psdProject(y, eps) // ensure positive definiteness, rescaled to a (symmetric) unit diagonal
+	for i := range x {
+		for j := i + 1; j < n; j++ {
+			c := .5 * (x[i][j] + x[j][i]) / math.Sqrt(x[i][i]*x[j][j])
+			x[i][j], x[j][i] = c, c
+		}
+	}
+	for i := range x {
+		x[i][i] = 1.
+	}
+	return x
+}
+
+// psdProject returns symmetric matrix a with its eigenvalues raised to at least eps
+func psdProject(a [][]float64, eps float64) [][]float64 {
+	w, v := jacobiEigen(a)
+	n := len(a)
+	x := make([][]float64, n)
+	for i := range x {
+		x[i] = make([]float64, n)
+		for j := range x[i] {
+			for k := 0; k < n; k++ {
+				x[i][j] += v[i][k] * math.Max(w[k], eps) * v[j][k]
+			}
+		}
+	}
+	return x
+}
+
+// jacobiEigen returns the eigenvalues and eigenvectors (columns) of symmetric matrix a by the cyclic Jacobi method
+// see section 11.1 in Press, W.H., S.A. Teukolsky, W.T. Vetterling, and B.P. Flannery, 2007. Numerical Recipes: The Art of Scientific Computing, third ed. Cambridge University Press. 1235pp.
+func jacobiEigen(a [][]float64) ([]float64, [][]float64) {
+	n := len(a)
+	m, v := copyMatrix(a), make([][]float64, n)
+	for i := range v {
+		v[i] = make([]float64, n)
+		v[i][i] = 1.
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += m[p][q] * m[p][q]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0. {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2. * m[p][q])
+				t := 1. / (math.Abs(theta) + math.Sqrt(theta*theta+1.))
+				if theta < 0. {
+					t = -t
+				}
+				c := 1. / math.Sqrt(t*t+1.)
+				s := t * c
+				for k := 0; k < n; k++ { // m = JᵀmJ
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := 0; k < n; k++ { // v = vJ
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = m[i][i]
+	}
+	return w, v
+}
+
+func copyMatrix(a [][]float64) [][]float64 {
+	c := make([][]float64, len(a))
+	for i, r := range a {
+		c[i] = append([]float64{}, r...)
+	}
+	return c
+}
@@ -0,0 +1,204 @@
+package jointdist
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// uniforms returns n seeded rows of p independent U[0,1] variates
+func uniforms(n, p int, seed int64) [][]float64 {
+	rng := rand.New(rand.NewSource(seed))
+	u := make([][]float64, n)
+	for k := range u {
+		u[k] = make([]float64, p)
+		for i := range u[k] {
+			u[k][i] = rng.Float64()
+		}
+	}
+	return u
+}
+
+// column returns column i of samples u
+func column(u [][]float64, i int) []float64 {
+	c := make([]float64, len(u))
+	for k, r := range u {
+		c[k] = r[i]
+	}
+	return c
+}
+
+// ranks returns the ranks of x (no ties)
+func ranks(x []float64) []float64 {
+	ix := make([]int, len(x))
+	for i := range ix {
+		ix[i] = i
+	}
+	sort.Slice(ix, func(a, b int) bool { return x[ix[a]] < x[ix[b]] })
+	r := make([]float64, len(x))
+	for k, i := range ix {
+		r[i] = float64(k)
+	}
+	return r
+}
+
+// spearman returns the rank correlation of x and y
+func spearman(x, y []float64) float64 {
+	rx, ry := ranks(x), ranks(y)
+	m := float64(len(x)-1) / 2.
+	sxy, sxx, syy := 0., 0., 0.
+	for i := range rx {
+		dx, dy := rx[i]-m, ry[i]-m
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// TestGaussianCopula checks the rank correlations and uniform marginals of seeded samples
+func TestGaussianCopula(t *testing.T) {
+	const n = 5000
+	for _, c := range []struct {
+		corr     [][]float64
+		spearman bool
+	}{
+		{[][]float64{{1., .5}, {.5, 1.}}, true},
+		{[][]float64{{1., -.7}, {-.7, 1.}}, true},
+		{[][]float64{{1., .8}, {.8, 1.}}, false},
+		{[][]float64{{1., .3, -.4}, {.3, 1., .2}, {-.4, .2, 1.}}, true},
+	} {
+		g, err := NewGaussianCopula(c.corr, c.spearman)
+		if err != nil {
+			t.Fatalf("%v: %v", c.corr, err)
+		}
+		if g.Corrected() || g.Ndim() != len(c.corr) {
+			t.Errorf("%v: Corrected, Ndim = %v, %v", c.corr, g.Corrected(), g.Ndim())
+		}
+		u := uniforms(n, len(c.corr), 1)
+		v, err := g.TransformAll(u)
+		if err != nil {
+			t.Fatalf("%v: %v", c.corr, err)
+		}
+		for i := range c.corr {
+			if ci := column(v, i); i == 0 {
+				for k := range ci {
+					if math.Abs(ci[k]-u[k][0]) > 1e-12 {
+						t.Fatalf("%v: first dimension changed at row %d", c.corr, k)
+					}
+				}
+			} else {
+				sort.Float64s(ci)
+				for _, p := range []float64{.25, .5, .75} {
+					if q := ci[int(p*n)]; math.Abs(q-p) > .03 {
+						t.Errorf("%v: dimension %d quantile %v = %.4f", c.corr, i, p, q)
+					}
+				}
+			}
+			for j := i + 1; j < len(c.corr); j++ {
+				want := c.corr[i][j]
+				if !c.spearman {
+					want = PearsonToSpearman(want)
+				}
+				if got := spearman(column(v, i), column(v, j)); math.Abs(got-want) > .04 {
+					t.Errorf("%v: Spearman rho[%d][%d] = %.4f, want %.4f", c.corr, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestNearestCorrelation checks that matrices that are not positive definite are replaced by a valid correlation matrix
+func TestNearestCorrelation(t *testing.T) {
+	// example of Higham (2002), section 4
+	a := [][]float64{{1., 1., 0.}, {1., 1., 1.}, {0., 1., 1.}}
+	want := [][]float64{{1., .7607, .1573}, {.7607, 1., .7607}, {.1573, .7607, 1.}}
+	x := NearestCorrelation(a)
+	for i := range want {
+		for j := range want {
+			if math.Abs(x[i][j]-want[i][j]) > 1e-3 {
+				t.Errorf("NearestCorrelation[%d][%d] = %.5f, want %.4f", i, j, x[i][j], want[i][j])
+			}
+		}
+	}
+
+	for _, corr := range [][][]float64{
+		a,
+		{{1., .9, .9}, {.9, 1., -.9}, {.9, -.9, 1.}},
+		{{1., .8, .8, -.8}, {.8, 1., .8, .8}, {.8, .8, 1., .8}, {-.8, .8, .8, 1.}},
+	} {
+		if cholesky(corr) != nil {
+			t.Fatalf("%v: test matrix is positive definite", corr)
+		}
+		g, err := NewGaussianCopula(corr, false)
+		if err != nil {
+			t.Fatalf("%v: %v", corr, err)
+		}
+		if !g.Corrected() {
+			t.Errorf("%v: not corrected", corr)
+		}
+		c := g.Correlation()
+		for i := range c {
+			if c[i][i] != 1. {
+				t.Errorf("%v: diagonal [%d] = %v", corr, i, c[i][i])
+			}
+			for j := range c {
+				if c[i][j] != c[j][i] || math.Abs(c[i][j]) > 1. {
+					t.Errorf("%v: invalid correlation [%d][%d] = %v", corr, i, j, c[i][j])
+				}
+			}
+		}
+		if w, _ := jacobiEigen(c); cholesky(c) == nil {
+			t.Errorf("%v: corrected matrix not positive definite, eigenvalues %v", corr, w)
+		}
+		if _, err := g.Transform(make([]float64, len(corr))); err != nil {
+			t.Errorf("%v: Transform: %v", corr, err)
+		}
+	}
+
+	// a positive definite correlation matrix is (nearly) its own nearest
+	b := [][]float64{{1., .3, -.4}, {.3, 1., .2}, {-.4, .2, 1.}}
+	x = NearestCorrelation(b)
+	for i := range b {
+		for j := range b {
+			if math.Abs(x[i][j]-b[i][j]) > 1e-8 {
+				t.Errorf("NearestCorrelation(valid)[%d][%d] = %v, want %v", i, j, x[i][j], b[i][j])
+			}
+		}
+	}
+}
+
+// TestGaussianCopulaErrors checks the errors returned for invalid correlation matrices and sample dimensions
+func TestGaussianCopulaErrors(t *testing.T) {
+	for _, c := range []struct {
+		corr [][]float64
+		err  error
+	}{
+		{nil, ErrDimensionMismatch},
+		{[][]float64{{1., .5}, {.5}}, ErrDimensionMismatch},
+		{[][]float64{{1., .5, 0.}, {.5, 1., 0.}}, ErrDimensionMismatch},
+		{[][]float64{{1., 1.5}, {1.5, 1.}}, ErrInvalidShape},
+		{[][]float64{{1., math.NaN()}, {math.NaN(), 1.}}, ErrInvalidShape},
+		{[][]float64{{.9, .5}, {.5, 1.}}, ErrInvalidShape},
+		{[][]float64{{1., .5}, {.4, 1.}}, ErrInvalidShape},
+	} {
+		if _, err := NewGaussianCopula(c.corr, true); !errors.Is(err, c.err) {
+			t.Errorf("%v: error %v, want %v", c.corr, err, c.err)
+		}
+	}
+
+	g, err := NewGaussianCopula([][]float64{{1., .5}, {.5, 1.}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range [][]float64{nil, {.5}, {.1, .2, .3}} {
+		if _, err := g.Transform(u); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Transform(%v): error %v", u, err)
+		}
+	}
+	if _, err := g.TransformAll([][]float64{{.1, .2}, {.3}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("TransformAll: error %v", err)
+	}
+}
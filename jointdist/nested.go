@@ -2,14 +2,14 @@ package jointdist
 
 import "math"
 
-// Nested transforms n variables such that 0.0 <= u1 <= ... <= un <= 1.0
+// Nested transforms n variables such that 0.0 <= u1 <= ... <= un <= 1.0,
+// i.e., the order statistics of n independent uniform variates (Nested2 for n = 2)
 func Nested(u ...float64) []float64 {
 	f, d := 1., len(u)
 	o := make([]float64, d)
-	for i, v := range u {
-		x := f * math.Pow(v, 1./float64(d-i))
-		o[i] = x
-		f *= x
+	for i := d - 1; i >= 0; i-- {
+		f *= math.Pow(u[i], 1./float64(i+1))
+		o[i] = f
 	}
 	return o
 }
@@ -0,0 +1,46 @@
+package jointdist
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNested checks that seeded samples are ordered, with the means i/(n+1) of uniform order statistics
+func TestNested(t *testing.T) {
+	const n = 10000
+	for _, d := range []int{1, 2, 3, 5} {
+		m := make([]float64, d)
+		for _, u := range uniforms(n, d, 1) {
+			o := Nested(u...)
+			for i, v := range o {
+				if v < 0. || v > 1. || (i > 0 && v < o[i-1]) {
+					t.Fatalf("Nested(%v) = %v not ordered in [0,1]", u, o)
+				}
+				m[i] += v / n
+			}
+			if d == 2 {
+				if a, b := Nested2(u[0], u[1]); math.Abs(a-o[0]) > 1e-15 || math.Abs(b-o[1]) > 1e-15 {
+					t.Fatalf("Nested2(%v) = %v, %v, Nested = %v", u, a, b, o)
+				}
+			}
+		}
+		for i, v := range m {
+			if want := float64(i+1) / float64(d+1); math.Abs(v-want) > .01 {
+				t.Errorf("d = %d: mean of u%d = %.4f, want %.4f", d, i+1, v, want)
+			}
+		}
+	}
+}
+
+// TestSumToOne checks that the transformed variables sum to one
+func TestSumToOne(t *testing.T) {
+	for _, u := range uniforms(100, 4, 1) {
+		s := 0.
+		for _, v := range SumToOne(u...) {
+			s += v
+		}
+		if math.Abs(s-1.) > 1e-14 {
+			t.Fatalf("sum(SumToOne(%v)) = %v", u, s)
+		}
+	}
+}
@@ -75,8 +75,9 @@ func collectBins(gobfp string) (sampler.Set, [][]float64) {
 	}
 
 	// intialize bins
-	bins, denom := make([][]float64, ss.Ndim), 0.
-	for i := 0; i < ss.Ndim; i++ {
+	np := len(ss.Samplers)
+	bins, denom := make([][]float64, np), 0.
+	for i := 0; i < np; i++ {
 		bins[i] = make([]float64, nbins)
 		for j := 0; j < nbins; j++ {
 			bins[i][j] = 0.
@@ -84,9 +85,9 @@ func collectBins(gobfp string) (sampler.Set, [][]float64) {
 	}
 
 	for _, v := range coll {
-		objfnc := v[0]
-		for i := 0; i < ss.Ndim; i++ {
-			ii := int(math.Floor(v[i+1] * float64(nbins)))
+		objfnc, u := v[0], ss.Expand(v[1:]) // samples are saved in the reduced space when groups are frozen
+		for i := 0; i < np; i++ {
+			ii := int(math.Floor(u[i] * float64(nbins)))
 			bins[i][ii] += objfnc
 		}
 		denom++
@@ -94,7 +95,7 @@ func collectBins(gobfp string) (sampler.Set, [][]float64) {
 
 	// normalize
 	denom /= float64(nbins)
-	for i := 0; i < ss.Ndim; i++ {
+	for i := 0; i < np; i++ {
 		for j := 0; j < nbins; j++ {
 			bins[i][j] /= denom
 		}
@@ -106,13 +107,13 @@ func writeToCsv(w *mmio.TXTwriter, gobfp string) {
 	ss, bins := collectBins(gobfp)
 	par, gnam := ss.ParameterNames(), mmio.FileName(gobfp, false)
 	for j := 0; j < nbins; j++ {
-		for i := 0; i < ss.Ndim; i++ {
+		for i := range ss.Samplers {
 			pnam := par[i]
 			var val float64
-			switch ss.Samplers[i].Dist {
-			case sampler.Constant:
+			switch {
+			case ss.Samplers[i].Dist == sampler.Constant, ss.Frozen(i):
 				continue
-			case sampler.LogLinear:
+			case ss.Samplers[i].IsLog():
 				pnam += " (log)"
 				val = math.Log10(ss.Samplers[i].Sample((float64(j) + .5) / float64(nbins)))
 			default:
@@ -137,10 +138,10 @@ func writePNG(mcdir, prfx string) {
 		ss, bins := collectBins(fp)
 		if len(pars) == 0 {
 			pars = ss.ParameterNames()
-			scr = make([][]float64, ss.Ndim)
-			xlab = make([][]string, ss.Ndim)
-			for i := 0; i < ss.Ndim; i++ {
-				if ss.Samplers[i].Dist == sampler.Constant {
+			scr = make([][]float64, len(ss.Samplers))
+			xlab = make([][]string, len(ss.Samplers))
+			for i := range ss.Samplers {
+				if ss.Samplers[i].Dist == sampler.Constant || ss.Frozen(i) {
 					continue
 				}
 				mfmt := "%.5f"
@@ -159,8 +160,8 @@ func writePNG(mcdir, prfx string) {
 				}
 			}
 		}
-		for i := 0; i < ss.Ndim; i++ {
-			if ss.Samplers[i].Dist == sampler.Constant {
+		for i := range ss.Samplers {
+			if xlab[i] == nil {
 				continue
 			}
 			for j := 0; j < nbins; j++ {
@@ -169,7 +170,7 @@ func writePNG(mcdir, prfx string) {
 		}
 	}
 
-	for i, n := range pars {
+	for i, n := range pars[:len(xlab)] {
 		if xlab[i] == nil {
 			continue
 		}
@@ -0,0 +1,458 @@
+// derived.go adds parameters that depend on others to a sampling Set:
+//  - derived parameters computed from an expression (see expr.go) or a Go callback of other parameters,
+//  - tied parameters that copy the value of another,
+//  - constraints enforced by transform: ordered parameters (jointdist.Nested) and
+//    parameters that sum to a total (jointdist.SumToOne), and
+//  - inequality constraints enforced by rejection, with diagnostics on rejection rates.
+// Derived and tied parameters are appended to the sample, in the order they were added;
+// the dimension of the (independently sampled) hypercube, Ndim, remains that of the (active) Samplers.
+
+package sampler
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/maseology/montecarlo/invdistr"
+	"github.com/maseology/montecarlo/jointdist"
+)
+
+// Derived parameter, computed from other parameters of a Set
+type Derived struct {
+	Name               string
+	Expr               string                             // expression of other parameters; a single parameter name (any name, not only identifiers) ties the two
+	Fn                 func(p map[string]float64) float64 `json:"-"` // Go callback used when Expr is empty (not saved to file)
+	Units, Description string
+}
+
+// Constraint on parameters, enforced by rejection
+type Constraint struct {
+	Name             string
+	Expr             string                          // inequality, e.g., "fc > wp"
+	Fn               func(p map[string]float64) bool `json:"-"` // Go callback used when Expr is empty (not saved to file)
+	tested, rejected int64
+}
+
+// TransformKind enum type
+type TransformKind int
+
+// TransformKind enums
+const (
+	Ordered TransformKind = iota // Names[0] <= Names[1] <= ... (common marginal distribution)
+	SumTo                        // sum of Names = Total
+)
+
+// String needed to return a TransformKind type as string
+func (k TransformKind) String() string {
+	return [...]string{"ordered", "sum-to"}[k]
+}
+
+// Transform enforces a constraint on (independently sampled) parameters by transforming their samples
+type Transform struct {
+	Kind  TransformKind
+	Names []string
+	Total float64 // SumTo only
+}
+
+// Rejection diagnostics of a constraint
+type Rejection struct {
+	Constraint       string
+	Tested, Rejected int64
+	Rate             float64
+}
+
+// program holds the compiled derived parameters, transforms and constraints of a Set
+type program struct {
+	derived []exprFunc
+	conds   []func(v []float64) bool
+	trans   [][]int         // Sampler indices of each Transform
+	fixed   map[int]float64 // values of frozen parameters, by Sampler index
+}
+
+// rejections counts the samples tested against, and rejected by, all constraints combined
+type rejections struct {
+	tested, rejected int64
+}
+
+// names returns the names of all parameters: those sampled, followed by those derived
+func (s *Set) names() []string {
+	n := make([]string, 0, len(s.Samplers)+len(s.Derived))
+	for _, m := range s.Samplers {
+		n = append(n, m.Name)
+	}
+	for _, d := range s.Derived {
+		n = append(n, d.Name)
+	}
+	return n
+}
+
+// index returns the position of parameter name in the sample vector
+func (s *Set) index(name string) int {
+	return indexOf(s.names(), name)
+}
+
+// indexOf returns the position of name in names, -1 if absent
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// compile returns the compiled program of the Set
+func (s *Set) compile() (*program, error) {
+	p := &program{}
+	names := s.names()
+	for i, d := range s.Derived {
+		var f exprFunc
+		switch k := indexOf(names[:len(s.Samplers)+i], d.Expr); {
+		case k >= 0: // tied, resolved by name rather than parsed, as names need not be identifiers
+			f = func(v []float64) float64 { return v[k] }
+		case d.Expr != "":
+			var err error
+			if f, err = compileExpr(d.Expr, names[:len(s.Samplers)+i]); err != nil {
+				return nil, fmt.Errorf("Set: derived parameter '%s': %w", d.Name, err)
+			}
+		case d.Fn != nil:
+			fn, nv := d.Fn, len(s.Samplers)+i
+			f = func(v []float64) float64 { return fn(named(names[:nv], v)) }
+		default:
+			return nil, fmt.Errorf("Set: derived parameter '%s' has neither an expression nor a callback (callbacks are not saved to file)", d.Name)
+		}
+		p.derived = append(p.derived, f)
+	}
+	for _, c := range s.Constraints {
+		switch {
+		case c.Expr != "":
+			f, err := compileCond(c.Expr, names)
+			if err != nil {
+				return nil, fmt.Errorf("Set: constraint '%s': %w", c.Name, err)
+			}
+			p.conds = append(p.conds, f)
+		case c.Fn != nil:
+			fn := c.Fn
+			p.conds = append(p.conds, func(v []float64) bool { return fn(named(names, v)) })
+		default:
+			return nil, fmt.Errorf("Set: constraint '%s' has neither an expression nor a callback (callbacks are not saved to file)", c.Name)
+		}
+	}
+	for _, t := range s.Transforms {
+		idx, err := s.transformIndices(t)
+		if err != nil {
+			return nil, err
+		}
+		p.trans = append(p.trans, idx)
+	}
+	p.fixed = s.frozen()
+	return p, nil
+}
+
+// named returns the parameter values by name
+func named(names []string, v []float64) map[string]float64 {
+	m := make(map[string]float64, len(names))
+	for i, n := range names {
+		m[n] = v[i]
+	}
+	return m
+}
+
+// Compile (re)compiles the derived parameters, transforms and constraints of the Set.
+// Needed only after the Set was read from file, otherwise compiled as they are added.
+func (s *Set) Compile() error {
+	p, err := s.compile()
+	if err != nil {
+		return err
+	}
+	s.prog = p
+	if s.rej == nil {
+		s.rej = &rejections{}
+	}
+	return nil
+}
+
+// program returns the compiled program, compiling once when the Set was not compiled
+// (e.g., read from file); the compiled program is cached atomically as samples may be drawn concurrently
+func (s *Set) program() (*program, error) {
+	if s.prog != nil {
+		return s.prog, nil
+	}
+	if p, ok := s.lazy.Load().(*program); ok {
+		return p, nil
+	}
+	p, err := s.compile()
+	if err != nil {
+		return nil, err
+	}
+	s.lazy.Store(p)
+	return p, nil
+}
+
+// checkName returns an error if name is empty or already used
+func (s *Set) checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("Set: parameter name missing")
+	}
+	if s.index(name) >= 0 {
+		return fmt.Errorf("Set: parameter '%s' already defined", name)
+	}
+	return nil
+}
+
+// add appends derived parameter d, rolling back if it does not compile
+func (s *Set) add(d *Derived) error {
+	if err := s.checkName(d.Name); err != nil {
+		return err
+	}
+	s.Derived = append(s.Derived, d)
+	if err := s.Compile(); err != nil {
+		s.Derived = s.Derived[:len(s.Derived)-1]
+		return err
+	}
+	return nil
+}
+
+// Derive adds a parameter computed from expression expr of other (previously defined) parameters,
+// e.g., s.Derive("fc", "wp + awc")
+func (s *Set) Derive(name, expr string) error {
+	return s.add(&Derived{Name: name, Expr: expr})
+}
+
+// DeriveFunc adds a parameter computed by a Go callback of other (previously defined) parameters, by name
+func (s *Set) DeriveFunc(name string, fn func(p map[string]float64) float64) error {
+	if fn == nil {
+		return fmt.Errorf("Set.DeriveFunc: no callback given for '%s'", name)
+	}
+	return s.add(&Derived{Name: name, Fn: fn})
+}
+
+// Tie adds a parameter that copies the value of another, e.g., the same conductivity for two layers
+func (s *Set) Tie(name, to string) error {
+	if s.index(to) < 0 {
+		return fmt.Errorf("Set.Tie: '%s' tied to '%s': %w", name, to, ErrUnknownParameter)
+	}
+	return s.add(&Derived{Name: name, Expr: to})
+}
+
+// constrain appends constraint c, rolling back if it does not compile
+func (s *Set) constrain(c *Constraint) error {
+	s.Constraints = append(s.Constraints, c)
+	if err := s.Compile(); err != nil {
+		s.Constraints = s.Constraints[:len(s.Constraints)-1]
+		return err
+	}
+	return nil
+}
+
+// Constrain adds an inequality constraint, e.g., s.Constrain("fc > wp"), enforced by rejection (see SampleE)
+func (s *Set) Constrain(expr string) error {
+	return s.constrain(&Constraint{Name: expr, Expr: expr})
+}
+
+// ConstrainFunc adds a constraint tested by a Go callback of the parameters (by name), enforced by rejection
+func (s *Set) ConstrainFunc(name string, fn func(p map[string]float64) bool) error {
+	if fn == nil {
+		return fmt.Errorf("Set.ConstrainFunc: no callback given for '%s'", name)
+	}
+	return s.constrain(&Constraint{Name: name, Fn: fn})
+}
+
+// transformIndices returns the Sampler indices of the parameters of Transform t
+func (s *Set) transformIndices(t *Transform) ([]int, error) {
+	if len(t.Names) < 2 {
+		return nil, fmt.Errorf("Set: %s transform requires at least 2 parameters, %d given: %w", t.Kind, len(t.Names), ErrDimensionMismatch)
+	}
+	idx := make([]int, len(t.Names))
+	for i, n := range t.Names {
+		idx[i] = -1
+		for j, m := range s.Samplers {
+			if m.Name == n {
+				idx[i] = j
+				break
+			}
+		}
+		if idx[i] < 0 {
+			return nil, fmt.Errorf("Set: %s transform: '%s' is not a sampled parameter: %w", t.Kind, n, ErrUnknownParameter)
+		}
+	}
+	return idx, nil
+}
+
+// transform appends Transform t after checking that its parameters are not already transformed
+func (s *Set) transform(t *Transform) error {
+	idx, err := s.transformIndices(t)
+	if err != nil {
+		return err
+	}
+	for _, n := range t.Names {
+		if s.Frozen(s.samplerIndex(n)) {
+			return fmt.Errorf("Set: '%s' is frozen", n)
+		}
+	}
+	for _, o := range s.Transforms {
+		for _, n := range o.Names {
+			for _, m := range t.Names {
+				if n == m {
+					return fmt.Errorf("Set: '%s' is already part of a %s transform", n, o.Kind)
+				}
+			}
+		}
+	}
+	switch t.Kind {
+	case Ordered:
+		m0 := s.Samplers[idx[0]]
+		for _, i := range idx[1:] {
+			m := s.Samplers[i]
+			if m.Dist != m0.Dist || m.Rmin != m0.Rmin || m.Rmax != m0.Rmax || !reflect.DeepEqual(m.Weights, m0.Weights) || !sameMap(m.Map, m0.Map) {
+				return fmt.Errorf("Set.Order: '%s' and '%s' must share the same distribution and range (constrain by rejection otherwise): %w", m0.Name, m.Name, ErrInvalidShape)
+			}
+		}
+	case SumTo:
+		if t.Total <= 0. {
+			return fmt.Errorf("Set.SumTo: invalid total %v: %w", t.Total, ErrInvalidBounds)
+		}
+		for _, i := range idx {
+			if s.Samplers[i].Rmin < 0. {
+				return fmt.Errorf("Set.SumTo: '%s' can be negative: %w", s.Samplers[i].Name, ErrInvalidBounds)
+			}
+		}
+	}
+	s.Transforms = append(s.Transforms, t)
+	return s.Compile()
+}
+
+// sameMap returns true if both distribution Maps are equal
+func sameMap(a, b *invdistr.Map) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	sa, erra := invdistr.SpecOf(a.Distr)
+	sb, errb := invdistr.SpecOf(b.Distr)
+	return erra == nil && errb == nil && a.Low == b.Low && a.High == b.High && a.Log == b.Log && reflect.DeepEqual(sa, sb)
+}
+
+// Order constrains sampled parameters such that names[0] <= names[1] <= ..., by transforming their
+// samples to the order statistics of U[0,1] (jointdist.Nested). The parameters must share the same
+// distribution and range; otherwise, define one from the other (Derive) or constrain by rejection.
+func (s *Set) Order(names ...string) error {
+	return s.transform(&Transform{Kind: Ordered, Names: names})
+}
+
+// SumTo constrains sampled (non-negative) parameters to sum to total by rescaling (jointdist.SumToOne),
+// e.g., fractions of a total. With Linear[0,1] samplers the fractions are not uniform over the simplex;
+// use exponential (e.g., gamma with k = 1) Mapped samplers for a flat Dirichlet distribution.
+func (s *Set) SumTo(total float64, names ...string) error {
+	return s.transform(&Transform{Kind: SumTo, Names: names, Total: total})
+}
+
+// apply returns the complete sample (sampled followed by derived parameters) from u, after transforms
+func (s *Set) apply(p *program, u []float64) ([]float64, error) {
+	uu := make([]float64, len(u))
+	copy(uu, u)
+	for k, t := range s.Transforms {
+		if t.Kind != Ordered {
+			continue
+		}
+		x := make([]float64, len(p.trans[k]))
+		for j, i := range p.trans[k] {
+			x[j] = uu[i]
+		}
+		for j, o := range jointdist.Nested(x...) {
+			uu[p.trans[k][j]] = o
+		}
+	}
+	v := make([]float64, len(s.Samplers), len(s.Samplers)+len(s.Derived))
+	for i, m := range s.Samplers {
+		if x, ok := p.fixed[i]; ok {
+			v[i] = x
+			continue
+		}
+		x, err := m.SampleE(uu[i])
+		if err != nil {
+			return nil, err
+		}
+		v[i] = x
+	}
+	for k, t := range s.Transforms {
+		if t.Kind != SumTo {
+			continue
+		}
+		x := make([]float64, len(p.trans[k]))
+		for j, i := range p.trans[k] {
+			x[j] = v[i]
+		}
+		for j, f := range jointdist.SumToOne(x...) {
+			v[p.trans[k][j]] = f * t.Total
+		}
+	}
+	for _, f := range p.derived {
+		v = append(v, f(v))
+	}
+	return v, nil
+}
+
+// feasible tests all constraints on sample v, counting rejections; returns the first constraint violated
+func (s *Set) feasible(p *program, v []float64) (bool, string) {
+	ok, first := true, ""
+	for k, c := range s.Constraints {
+		atomic.AddInt64(&c.tested, 1)
+		if !p.conds[k](v) {
+			atomic.AddInt64(&c.rejected, 1)
+			if ok {
+				ok, first = false, c.Name
+			}
+		}
+	}
+	if len(s.Constraints) > 0 && s.rej != nil {
+		atomic.AddInt64(&s.rej.tested, 1)
+		if !ok {
+			atomic.AddInt64(&s.rej.rejected, 1)
+		}
+	}
+	return ok, first
+}
+
+// Feasible returns true if the complete sample v (sampled followed by derived parameters)
+// satisfies all constraints; rejections are counted (see Rejections)
+func (s *Set) Feasible(v []float64) bool {
+	p, err := s.program()
+	if err != nil || len(v) != len(s.Samplers)+len(s.Derived) {
+		return false
+	}
+	ok, _ := s.feasible(p, v)
+	return ok
+}
+
+// Rejections returns the rejection diagnostics of each constraint, followed by that of all constraints combined
+func (s *Set) Rejections() []Rejection {
+	rate := func(t, r int64) float64 {
+		if t == 0 {
+			return 0.
+		}
+		return float64(r) / float64(t)
+	}
+	o := make([]Rejection, 0, len(s.Constraints)+1)
+	for _, c := range s.Constraints {
+		t, r := atomic.LoadInt64(&c.tested), atomic.LoadInt64(&c.rejected)
+		o = append(o, Rejection{Constraint: c.Name, Tested: t, Rejected: r, Rate: rate(t, r)})
+	}
+	if s.rej != nil {
+		t, r := atomic.LoadInt64(&s.rej.tested), atomic.LoadInt64(&s.rej.rejected)
+		o = append(o, Rejection{Constraint: "all", Tested: t, Rejected: r, Rate: rate(t, r)})
+	}
+	return o
+}
+
+// ResetRejections zeroes the rejection counts
+func (s *Set) ResetRejections() {
+	for _, c := range s.Constraints {
+		atomic.StoreInt64(&c.tested, 0)
+		atomic.StoreInt64(&c.rejected, 0)
+	}
+	if s.rej != nil {
+		atomic.StoreInt64(&s.rej.tested, 0)
+		atomic.StoreInt64(&s.rej.rejected, 0)
+	}
+}
@@ -0,0 +1,27 @@
+package sampler
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestTie checks that parameters are tied by name, including names the expression parser does not accept
+func TestTie(t *testing.T) {
+	s := NewSet([]*Sampler{New("k-layer 1.a", Linear, 1., 3.), New("b", Linear, 0., 1.)})
+	if err := s.Tie("k-layer 2.a", "k-layer 1.a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Tie("k 3", "k-layer 2.a"); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.Sample([]float64{.25, .5}); len(v) != 4 || v[2] != 1.5 || v[3] != 1.5 {
+		t.Errorf("Sample = %v", v)
+	}
+	if err := s.Tie("c", "k-layer 9"); !errors.Is(err, ErrUnknownParameter) {
+		t.Errorf("Tie to an unknown parameter: error = %v", err)
+	}
+	r := &Set{Samplers: s.Samplers, Ndim: s.Ndim, Derived: s.Derived} // as read from file
+	if v, err := r.SampleE([]float64{.75, .5}); err != nil || v[3] != 2.5 {
+		t.Errorf("SampleE of a Set read from file = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,20 @@
+package sampler
+
+import (
+	"errors"
+
+	"github.com/maseology/montecarlo/invdistr"
+)
+
+// Errors returned (wrapped) by the Sampler constructors and methods, test using errors.Is.
+// Bounds, shape and dimension errors are those of package invdistr, such that
+// errors raised by a Sampler's distribution Map match as well.
+var (
+	ErrInvalidBounds       = invdistr.ErrInvalidBounds
+	ErrInvalidShape        = invdistr.ErrInvalidShape
+	ErrDimensionMismatch   = invdistr.ErrDimensionMismatch
+	ErrUnknownDistribution = errors.New("unknown distribution")
+	ErrUnknownParameter    = errors.New("unknown parameter")
+	ErrRejected            = errors.New("sample rejected by constraint")
+	ErrUnsupported         = errors.New("not supported by sampling-plan files")
+)
@@ -0,0 +1,292 @@
+// expr.go a small arithmetic expression parser used to define derived parameters and constraints
+// from other parameters by name, e.g., "fc - wp", "0.5*(kh + kv)", "10^logk" or "max(a, b)".
+// Supported: numbers, parameter names, + - * / ^ (right associative), unary minus, parentheses and
+// the functions abs, exp, ln (or log), log10, sqrt, min, max and pow. Conditions compare two
+// expressions with <, <=, > or >=.
+
+package sampler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type exprFunc func(v []float64) float64
+
+type exprParser struct {
+	s     string
+	pos   int
+	names map[string]int // parameter name -> index in the sample vector
+}
+
+// compileExpr returns the evaluator of expression s, where parameter names index into the sample vector
+func compileExpr(s string, names []string) (exprFunc, error) {
+	p := newExprParser(s, names)
+	f, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skip(); p.pos < len(p.s) {
+		return nil, p.errorf("unexpected '%s'", p.s[p.pos:])
+	}
+	return f, nil
+}
+
+// compileCond returns the evaluator of condition s, e.g., "fc > wp"
+func compileCond(s string, names []string) (func(v []float64) bool, error) {
+	p := newExprParser(s, names)
+	l, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	p.skip()
+	var op string
+	for _, o := range []string{"<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.s[p.pos:], o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expecting a comparison (<, <=, >, >=)")
+	}
+	p.pos += len(op)
+	r, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skip(); p.pos < len(p.s) {
+		return nil, p.errorf("unexpected '%s'", p.s[p.pos:])
+	}
+	switch op {
+	case "<":
+		return func(v []float64) bool { return l(v) < r(v) }, nil
+	case "<=":
+		return func(v []float64) bool { return l(v) <= r(v) }, nil
+	case ">":
+		return func(v []float64) bool { return l(v) > r(v) }, nil
+	default:
+		return func(v []float64) bool { return l(v) >= r(v) }, nil
+	}
+}
+
+func newExprParser(s string, names []string) *exprParser {
+	m := make(map[string]int, len(names))
+	for i, n := range names {
+		m[n] = i
+	}
+	return &exprParser{s: s, names: m}
+}
+
+func (p *exprParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("expression '%s': position %d: %s", p.s, p.pos+1, fmt.Sprintf(format, a...))
+}
+
+func (p *exprParser) skip() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// next skips white space and consumes c if it is the next character
+func (p *exprParser) next(c byte) bool {
+	if p.skip(); p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expr := term { ('+' | '-') term }
+func (p *exprParser) expr() (exprFunc, error) {
+	f, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.next('+'):
+			g, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			l := f
+			f = func(v []float64) float64 { return l(v) + g(v) }
+		case p.next('-'):
+			g, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			l := f
+			f = func(v []float64) float64 { return l(v) - g(v) }
+		default:
+			return f, nil
+		}
+	}
+}
+
+// term := unary { ('*' | '/') unary }
+func (p *exprParser) term() (exprFunc, error) {
+	f, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.next('*'):
+			g, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			l := f
+			f = func(v []float64) float64 { return l(v) * g(v) }
+		case p.next('/'):
+			g, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			l := f
+			f = func(v []float64) float64 { return l(v) / g(v) }
+		default:
+			return f, nil
+		}
+	}
+}
+
+// unary := '-' unary | power
+func (p *exprParser) unary() (exprFunc, error) {
+	if p.next('-') {
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v []float64) float64 { return -f(v) }, nil
+	}
+	p.next('+')
+	return p.power()
+}
+
+// power := primary [ '^' unary ]
+func (p *exprParser) power() (exprFunc, error) {
+	f, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if p.next('^') {
+		g, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v []float64) float64 { return math.Pow(f(v), g(v)) }, nil
+	}
+	return f, nil
+}
+
+// primary := number | name | function '(' expr { ',' expr } ')' | '(' expr ')'
+func (p *exprParser) primary() (exprFunc, error) {
+	p.skip()
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	if p.next('(') {
+		f, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(')') {
+			return nil, p.errorf("expecting ')'")
+		}
+		return f, nil
+	}
+	c := rune(p.s[p.pos])
+	switch {
+	case unicode.IsDigit(c) || c == '.':
+		i := p.pos
+		for p.pos < len(p.s) && (unicode.IsDigit(rune(p.s[p.pos])) || p.s[p.pos] == '.') {
+			p.pos++
+		}
+		if p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+			p.pos++
+			if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+				p.pos++
+			}
+			for p.pos < len(p.s) && unicode.IsDigit(rune(p.s[p.pos])) {
+				p.pos++
+			}
+		}
+		t := p.s[i:p.pos]
+		x, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			p.pos = i
+			return nil, p.errorf("invalid number '%s'", t)
+		}
+		return func([]float64) float64 { return x }, nil
+	case unicode.IsLetter(c) || c == '_':
+		i := p.pos
+		for p.pos < len(p.s) && (unicode.IsLetter(rune(p.s[p.pos])) || unicode.IsDigit(rune(p.s[p.pos])) || p.s[p.pos] == '_' || p.s[p.pos] == '.') {
+			p.pos++
+		}
+		n := p.s[i:p.pos]
+		if p.next('(') {
+			return p.function(n)
+		}
+		k, ok := p.names[n]
+		if !ok {
+			p.pos = i
+			return nil, p.errorf("unknown parameter '%s'", n)
+		}
+		return func(v []float64) float64 { return v[k] }, nil
+	}
+	return nil, p.errorf("unexpected '%c'", c)
+}
+
+// function parses the arguments of function n, following its opening parenthesis
+func (p *exprParser) function(n string) (exprFunc, error) {
+	var args []exprFunc
+	if !p.next(')') {
+		for {
+			f, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, f)
+			if p.next(')') {
+				break
+			}
+			if !p.next(',') {
+				return nil, p.errorf("expecting ',' or ')'")
+			}
+		}
+	}
+	unary := map[string]func(float64) float64{
+		"abs":   math.Abs,
+		"exp":   math.Exp,
+		"ln":    math.Log,
+		"log":   math.Log,
+		"log10": math.Log10,
+		"sqrt":  math.Sqrt,
+	}
+	binary := map[string]func(float64, float64) float64{
+		"min": math.Min,
+		"max": math.Max,
+		"pow": math.Pow,
+	}
+	if g, ok := unary[n]; ok {
+		if len(args) != 1 {
+			return nil, p.errorf("%s requires 1 argument, %d given", n, len(args))
+		}
+		a := args[0]
+		return func(v []float64) float64 { return g(a(v)) }, nil
+	}
+	if g, ok := binary[n]; ok {
+		if len(args) != 2 {
+			return nil, p.errorf("%s requires 2 arguments, %d given", n, len(args))
+		}
+		a, b := args[0], args[1]
+		return func(v []float64) float64 { return g(a(v), b(v)) }, nil
+	}
+	return nil, p.errorf("unknown function '%s'", n)
+}
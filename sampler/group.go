@@ -0,0 +1,261 @@
+// group.go adds named groups of parameters to a sampling Set, that can be frozen (held at given values)
+// while the others are sampled, e.g., snow parameters during a summer calibration. Ndim then counts
+// the active parameters only: sampling plans explore the reduced space, and Sample (or Expand)
+// returns the full parameter vector for evaluation and saving. Active groups may also be sampled
+// with different densities (Design).
+
+package sampler
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/maseology/montecarlo/smpln"
+)
+
+// Group of named parameters
+type Group struct {
+	Name   string
+	Names  []string
+	Frozen bool
+	Values []float64 // values held while frozen
+}
+
+// group returns the named Group
+func (s *Set) group(name string) (*Group, error) {
+	for _, g := range s.Groups {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+	return nil, fmt.Errorf("Set: group '%s': %w", name, ErrUnknownParameter)
+}
+
+// samplerIndex returns the position of sampled parameter name, -1 if not found
+func (s *Set) samplerIndex(name string) int {
+	for i, m := range s.Samplers {
+		if m.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddGroup adds a named group of sampled parameters; a parameter can belong to a single group
+func (s *Set) AddGroup(name string, names ...string) error {
+	if name == "" {
+		return fmt.Errorf("Set.AddGroup: group name missing")
+	}
+	if _, err := s.group(name); err == nil {
+		return fmt.Errorf("Set.AddGroup: group '%s' already defined", name)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("Set.AddGroup: group '%s': no parameters given: %w", name, ErrDimensionMismatch)
+	}
+	for _, n := range names {
+		if s.samplerIndex(n) < 0 {
+			return fmt.Errorf("Set.AddGroup: group '%s': '%s' is not a sampled parameter: %w", name, n, ErrUnknownParameter)
+		}
+		for _, g := range s.Groups {
+			for _, m := range g.Names {
+				if m == n {
+					return fmt.Errorf("Set.AddGroup: group '%s': '%s' already belongs to group '%s'", name, n, g.Name)
+				}
+			}
+		}
+	}
+	s.Groups = append(s.Groups, &Group{Name: name, Names: append([]string{}, names...)})
+	return nil
+}
+
+// Freeze holds the parameters of group name at the given values (nil for their medians),
+// removing them from the sampled dimensions; values must be attainable by their Samplers
+func (s *Set) Freeze(name string, values []float64) error {
+	g, err := s.group(name)
+	if err != nil {
+		return err
+	}
+	if values == nil {
+		values = make([]float64, len(g.Names))
+		for j, n := range g.Names {
+			values[j] = s.Samplers[s.samplerIndex(n)].Sample(.5)
+		}
+	}
+	if len(values) != len(g.Names) {
+		return fmt.Errorf("Set.Freeze: group '%s': %d values given for %d parameters: %w", name, len(values), len(g.Names), ErrDimensionMismatch)
+	}
+	for j, n := range g.Names {
+		if err := s.Samplers[s.samplerIndex(n)].attainable(values[j]); err != nil {
+			return fmt.Errorf("Set.Freeze: group '%s': %w", name, err)
+		}
+		for _, t := range s.Transforms {
+			for _, m := range t.Names {
+				if m == n {
+					return fmt.Errorf("Set.Freeze: group '%s': '%s' is part of a %s transform", name, n, t.Kind)
+				}
+			}
+		}
+	}
+	g.Frozen, g.Values = true, append([]float64{}, values...)
+	return s.regroup()
+}
+
+// Unfreeze returns the parameters of group name to the sampled dimensions
+func (s *Set) Unfreeze(name string) error {
+	g, err := s.group(name)
+	if err != nil {
+		return err
+	}
+	g.Frozen, g.Values = false, nil
+	return s.regroup()
+}
+
+// regroup resets the number of sampled dimensions and recompiles the Set
+func (s *Set) regroup() error {
+	s.Ndim = len(s.active())
+	return s.Compile()
+}
+
+// frozen returns the values of frozen parameters, by Sampler index
+func (s *Set) frozen() map[int]float64 {
+	f := make(map[int]float64)
+	for _, g := range s.Groups {
+		if !g.Frozen {
+			continue
+		}
+		for j, n := range g.Names {
+			if i := s.samplerIndex(n); i >= 0 && j < len(g.Values) {
+				f[i] = g.Values[j]
+			}
+		}
+	}
+	return f
+}
+
+// Frozen returns true if Sampler i is held at a frozen value
+func (s *Set) Frozen(i int) bool {
+	_, ok := s.frozen()[i]
+	return ok
+}
+
+// active returns the Sampler indices of the sampled (not frozen) parameters
+func (s *Set) active() []int {
+	f := s.frozen()
+	a := make([]int, 0, len(s.Samplers))
+	for i := range s.Samplers {
+		if _, ok := f[i]; !ok {
+			a = append(a, i)
+		}
+	}
+	return a
+}
+
+// ActiveNames returns the names of the sampled (not frozen) parameters, in the order of the reduced space
+func (s *Set) ActiveNames() []string {
+	a := s.active()
+	n := make([]string, len(a))
+	for j, i := range a {
+		n[j] = s.Samplers[i].Name
+	}
+	return n
+}
+
+// Expand returns the point in the full U^n from a point u of the reduced (active) space,
+// where frozen parameters are set to the u that maps to their value
+func (s *Set) Expand(u []float64) []float64 {
+	if len(u) == len(s.Samplers) {
+		return append([]float64{}, u...)
+	}
+	uu := make([]float64, len(s.Samplers))
+	f := s.frozen()
+	for i, m := range s.Samplers {
+		if v, ok := f[i]; ok {
+			uu[i] = m.Unsample(v)
+		}
+	}
+	for j, i := range s.active() {
+		uu[i] = u[j]
+	}
+	return uu
+}
+
+// Reduce returns the point in the reduced (active) space from a point u of the full U^n
+func (s *Set) Reduce(u []float64) []float64 {
+	a := s.active()
+	r := make([]float64, len(a))
+	for j, i := range a {
+		r[j] = u[i]
+	}
+	return r
+}
+
+// full returns u in the full U^n, given either a point of the reduced (active) or the full space
+func (s *Set) full(u []float64) ([]float64, error) {
+	switch len(u) {
+	case len(s.Samplers):
+		return u, nil
+	case s.Ndim:
+		return s.Expand(u), nil
+	}
+	return nil, fmt.Errorf("Set.Sample error: %d samples given for %d parameters: %w", len(u), s.Ndim, ErrDimensionMismatch)
+}
+
+// Design returns a crossed sampling design of the reduced (active) space, where every active
+// group is sampled with a Latin hypercube of its own density n[group]; active parameters
+// outside of any group are sampled together with density n[""]. The design is the
+// Cartesian product of the group samples (prod(n) points).
+func (s *Set) Design(rng *rand.Rand, n map[string]int, midpoint bool) ([][]float64, error) {
+	pos := make(map[int]int) // Sampler index -> position in the reduced space
+	for j, i := range s.active() {
+		pos[i] = j
+	}
+	var names []string
+	dims := make(map[string][]int)
+	for _, g := range s.Groups {
+		if g.Frozen {
+			continue
+		}
+		names = append(names, g.Name)
+		for _, m := range g.Names {
+			j := pos[s.samplerIndex(m)]
+			dims[g.Name] = append(dims[g.Name], j)
+			delete(pos, s.samplerIndex(m))
+		}
+	}
+	if len(pos) > 0 {
+		names = append(names, "")
+		for _, i := range s.active() {
+			if j, ok := pos[i]; ok {
+				dims[""] = append(dims[""], j)
+			}
+		}
+	}
+
+	nt, lhcs := 1, make([]*smpln.LatinHyperCube, len(names))
+	for k, g := range names {
+		ng, ok := n[g]
+		if !ok {
+			return nil, fmt.Errorf("Set.Design: no sample density given for group '%s': %w", g, ErrDimensionMismatch)
+		}
+		lhc, err := smpln.NewLHCE(rng, ng, len(dims[g]), midpoint)
+		if err != nil {
+			return nil, fmt.Errorf("Set.Design: group '%s': %w", g, err)
+		}
+		lhcs[k] = lhc
+		nt *= ng
+	}
+
+	d := make([][]float64, nt)
+	for r := range d {
+		d[r] = make([]float64, s.Ndim)
+		q := r
+		for k, g := range names {
+			ng := lhcs[k].SampleSize()
+			for jj, j := range dims[g] {
+				d[r][j] = lhcs[k].U[jj][q%ng]
+			}
+			q /= ng
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,287 @@
+// hierarchy.go a hierarchical (spatially distributed) parameterization built on a sampling Set:
+// a few sampled (or derived) parameters act as multipliers or offsets on classes of spatially varying
+// base values (e.g., conductivity per soil type, roughness per subbasin). Sampling plans (GenerateSamples)
+// then explore the low-dimensional space of the Set, while evaluation functions receive the full
+// physical parameterization.
+
+package sampler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Operation enum type
+type Operation int
+
+// Operation enums: how the Set parameter modifies the base values of a Class
+const (
+	Multiply    Operation = iota // base * p
+	Add                          // base + p
+	LogMultiply                  // base * 10^p (i.e., an offset in log10 space)
+)
+
+// String needed to return an Operation type as string
+func (o Operation) String() string {
+	return [...]string{"multiply", "add", "log-multiply"}[o]
+}
+
+// Class of distributed parameters, expanded from base values by a single parameter of the Set
+type Class struct {
+	Name      string
+	Parameter string // name of the (sampled or derived) Set parameter applied to the base values
+	Op        Operation
+	Base      []float64 // base values, e.g., per soil type or subbasin
+	Labels    []string  // optional labels of the base values
+	Low, High float64   // physical limits applied to the expanded values (ignored when Low >= High)
+}
+
+// Hierarchy of distributed parameter classes built on a sampling Set
+type Hierarchy struct {
+	Set     *Set
+	Classes []*Class
+
+	idx []int // Set index of the parameter of each Class, resolved as classes are added
+}
+
+// Parameters is the full (physical) parameterization expanded from a sample of the Set
+type Parameters struct {
+	Global      []float64   // sample of the Set (sampled followed by derived parameters)
+	Distributed [][]float64 // expanded values of each Class
+}
+
+// NewHierarchy constructs a new hierarchical parameterization on Set s
+func NewHierarchy(s *Set) *Hierarchy {
+	return &Hierarchy{Set: s}
+}
+
+// Ndim returns the number of sampled dimensions (that of the Set)
+func (h *Hierarchy) Ndim() int { return h.Set.Ndim }
+
+// AddClass adds a class of distributed parameters, expanded from base values by Set parameter param
+func (h *Hierarchy) AddClass(name, param string, op Operation, base []float64) (*Class, error) {
+	c := &Class{Name: name, Parameter: param, Op: op, Base: append([]float64{}, base...)}
+	if err := h.add(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (h *Hierarchy) add(c *Class) error {
+	if c.Name == "" {
+		return fmt.Errorf("Hierarchy.AddClass: class name missing")
+	}
+	for _, o := range h.Classes {
+		if o.Name == c.Name {
+			return fmt.Errorf("Hierarchy.AddClass: class '%s' already defined", c.Name)
+		}
+	}
+	if c.Op < Multiply || c.Op > LogMultiply {
+		return fmt.Errorf("Hierarchy.AddClass: class '%s': unknown operation %d", c.Name, int(c.Op))
+	}
+	if len(c.Base) == 0 {
+		return fmt.Errorf("Hierarchy.AddClass: class '%s': no base values given: %w", c.Name, ErrDimensionMismatch)
+	}
+	if c.Labels != nil && len(c.Labels) != len(c.Base) {
+		return fmt.Errorf("Hierarchy.AddClass: class '%s': %d labels given for %d base values: %w", c.Name, len(c.Labels), len(c.Base), ErrDimensionMismatch)
+	}
+	k := h.Set.index(c.Parameter)
+	if k < 0 {
+		return fmt.Errorf("Hierarchy.AddClass: class '%s': '%s': %w", c.Name, c.Parameter, ErrUnknownParameter)
+	}
+	idx, err := h.indices()
+	if err != nil {
+		return err
+	}
+	h.Classes, h.idx = append(h.Classes, c), append(idx, k)
+	return nil
+}
+
+// indices returns the Set index of the parameter of each Class, resolving them again
+// (with index -1 and an error for unknown parameters) when Classes were set directly
+func (h *Hierarchy) indices() ([]int, error) {
+	if len(h.idx) == len(h.Classes) {
+		return h.idx, nil
+	}
+	idx := make([]int, len(h.Classes))
+	var err error
+	for i, c := range h.Classes {
+		if idx[i] = h.Set.index(c.Parameter); idx[i] < 0 && err == nil {
+			err = fmt.Errorf("Hierarchy: class '%s': '%s': %w", c.Name, c.Parameter, ErrUnknownParameter)
+		}
+	}
+	return idx, err
+}
+
+// Expand returns the values of the class given the value p of its Set parameter
+func (c *Class) Expand(p float64) []float64 {
+	v := make([]float64, len(c.Base))
+	for i, b := range c.Base {
+		switch c.Op {
+		case Multiply:
+			v[i] = b * p
+		case Add:
+			v[i] = b + p
+		case LogMultiply:
+			v[i] = b * math.Pow(10., p)
+		}
+		if c.Low < c.High {
+			v[i] = math.Min(math.Max(v[i], c.Low), c.High)
+		}
+	}
+	return v
+}
+
+// Expand returns the full parameterization from a (complete) sample v of the Set;
+// classes of parameters unknown to the Set are left nil (see Sample)
+func (h *Hierarchy) Expand(v []float64) *Parameters {
+	idx, _ := h.indices()
+	return h.expand(idx, v)
+}
+
+func (h *Hierarchy) expand(idx []int, v []float64) *Parameters {
+	p := &Parameters{Global: v, Distributed: make([][]float64, len(h.Classes))}
+	for i, c := range h.Classes {
+		if idx[i] >= 0 && idx[i] < len(v) {
+			p.Distributed[i] = c.Expand(v[idx[i]])
+		}
+	}
+	return p
+}
+
+// Sample returns the full parameterization from U^n (see Set.SampleE); samples violating
+// a constraint of the Set are returned with an error wrapping ErrRejected
+func (h *Hierarchy) Sample(u []float64) (*Parameters, error) {
+	idx, err := h.indices()
+	if err != nil {
+		return nil, err
+	}
+	v, err := h.Set.SampleE(u)
+	if v == nil {
+		return nil, err
+	}
+	return h.expand(idx, v), err
+}
+
+// Class returns the expanded values of class name
+func (h *Hierarchy) Class(p *Parameters, name string) ([]float64, error) {
+	for i, c := range h.Classes {
+		if c.Name == name {
+			return p.Distributed[i], nil
+		}
+	}
+	return nil, fmt.Errorf("Hierarchy.Class: '%s': %w", name, ErrUnknownParameter)
+}
+
+// Func adapts an evaluation function of the full parameterization to one of U^n, as used by
+// GenerateSamples and GenerateTop; samples rejected by a constraint of the Set, or that cannot
+// be drawn (see Sample), return infeasible
+func (h *Hierarchy) Func(eval func(p *Parameters, i int) float64, infeasible float64) func(u []float64, i int) float64 {
+	return h.FuncE(eval, infeasible, nil)
+}
+
+// FuncE adapts an evaluation function as Func, additionally passing errors other than
+// rejections (e.g., u of the wrong dimension) to onError, when not nil, before returning infeasible
+func (h *Hierarchy) FuncE(eval func(p *Parameters, i int) float64, infeasible float64, onError func(u []float64, i int, err error)) func(u []float64, i int) float64 {
+	return func(u []float64, i int) float64 {
+		p, err := h.Sample(u)
+		if err != nil {
+			if !errors.Is(err, ErrRejected) && onError != nil {
+				onError(u, i, err)
+			}
+			return infeasible
+		}
+		return eval(p, i)
+	}
+}
+
+// BaseTable of spatially varying base values: one row per spatial unit (label), one column per class
+type BaseTable struct {
+	Labels, Columns []string
+	Values          [][]float64 // [column][row]
+}
+
+// Column returns the base values of column name
+func (t *BaseTable) Column(name string) ([]float64, error) {
+	for j, c := range t.Columns {
+		if c == name {
+			return t.Values[j], nil
+		}
+	}
+	return nil, fmt.Errorf("BaseTable: column '%s': %w", name, ErrUnknownParameter)
+}
+
+// AddClasses adds a class for every column of base table t listed in params (column name to
+// Set parameter name); when params is nil, every column is applied the Set parameter of the same name
+func (h *Hierarchy) AddClasses(t *BaseTable, op Operation, params map[string]string) error {
+	for j, n := range t.Columns {
+		p := n
+		if params != nil {
+			var ok bool
+			if p, ok = params[n]; !ok {
+				continue
+			}
+		}
+		c := &Class{Name: n, Parameter: p, Op: op, Base: append([]float64{}, t.Values[j]...), Labels: append([]string{}, t.Labels...)}
+		if err := h.add(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBaseTable reads a CSV table of base values with a header row, where the first column
+// holds the labels of the spatial units (e.g., soil type or subbasin ID) and the remaining
+// columns the base values of each class
+func ReadBaseTable(fp string) (*BaseTable, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readBaseTable(fp, f)
+}
+
+func readBaseTable(fp string, r io.Reader) (*BaseTable, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+	hdr, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("sampler.ReadBaseTable %s: %w", fp, err)
+	}
+	if len(hdr) < 2 {
+		return nil, fmt.Errorf("sampler.ReadBaseTable %s: line 1: expecting a label column followed by at least one column of base values", fp)
+	}
+	t := &BaseTable{Columns: make([]string, len(hdr)-1), Values: make([][]float64, len(hdr)-1)}
+	for j, h := range hdr[1:] {
+		t.Columns[j] = strings.TrimSpace(h)
+	}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("sampler.ReadBaseTable %s: %w", fp, err)
+		}
+		l, _ := cr.FieldPos(0)
+		t.Labels = append(t.Labels, strings.TrimSpace(rec[0]))
+		for j, s := range rec[1:] {
+			x, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err != nil {
+				return nil, fmt.Errorf("sampler.ReadBaseTable %s: line %d: column '%s': invalid value '%s'", fp, l, t.Columns[j], s)
+			}
+			t.Values[j] = append(t.Values[j], x)
+		}
+	}
+	if len(t.Labels) == 0 {
+		return nil, fmt.Errorf("sampler.ReadBaseTable %s: no rows found", fp)
+	}
+	return t, nil
+}
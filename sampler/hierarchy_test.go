@@ -0,0 +1,48 @@
+package sampler
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestHierarchyFunc checks that Func returns the fallback value, rather than exiting, on samples that cannot be drawn
+func TestHierarchyFunc(t *testing.T) {
+	s := NewSet([]*Sampler{New("k", Linear, 1., 3.), New("n", Linear, 0., 1.)})
+	if err := s.Constrain("k < 2.5"); err != nil {
+		t.Fatal(err)
+	}
+	h := NewHierarchy(s)
+	if _, err := h.AddClass("ks", "k", Multiply, []float64{1., 10.}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.AddClass("ns", "n", Add, []float64{.1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.AddClass("x", "missing", Add, []float64{.1}); !errors.Is(err, ErrUnknownParameter) {
+		t.Errorf("AddClass of an unknown parameter: error = %v", err)
+	}
+	var errs []error
+	f := h.FuncE(func(p *Parameters, i int) float64 { return p.Distributed[0][1] + p.Distributed[1][0] }, -1., func(u []float64, i int, err error) {
+		errs = append(errs, err)
+	})
+	if y := f([]float64{.25, .5}, 0); y != 15.6 {
+		t.Errorf("f(.25, .5) = %v", y)
+	}
+	if y := f([]float64{.9, .5}, 0); y != -1. || len(errs) != 0 {
+		t.Errorf("rejected sample: f = %v, errors %v", y, errs)
+	}
+	if y := f([]float64{.5}, 0); y != -1. || len(errs) != 1 || !errors.Is(errs[0], ErrDimensionMismatch) {
+		t.Errorf("sample of the wrong dimension: f = %v, errors %v", y, errs)
+	}
+	if y := h.Func(func(p *Parameters, i int) float64 { return 0. }, -2.)([]float64{.5}, 0); y != -2. {
+		t.Errorf("Func of the wrong dimension = %v", y)
+	}
+
+	h.Classes = append(h.Classes, &Class{Name: "x", Parameter: "missing", Base: []float64{1.}})
+	if _, err := h.Sample([]float64{.25, .5}); !errors.Is(err, ErrUnknownParameter) {
+		t.Errorf("Sample with a class set directly of an unknown parameter: error = %v", err)
+	}
+	if p := h.Expand([]float64{2., .5}); p.Distributed[0][1] != 20. || p.Distributed[2] != nil {
+		t.Errorf("Expand = %v", p.Distributed)
+	}
+}
@@ -0,0 +1,424 @@
+package sampler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/maseology/montecarlo/invdistr"
+	"gopkg.in/yaml.v3"
+)
+
+// Param is a sampling-plan file entry describing a single parameter. Distribution is one of
+// constant, linear, log-linear, integer, categorical, or any invdistr.Spec kind (e.g., triangle,
+// normal, mixture) with its distribution parameters given in Params. For invdistr kinds, Min and Max
+// are the bounds of the Map (omit both for distributions returning values in parameter space), and
+// Log sets the distribution in base-10 log space.
+type Param struct {
+	Name         string          `json:"name" yaml:"name"`
+	Distribution string          `json:"distribution" yaml:"distribution"`
+	Min          float64         `json:"min" yaml:"min"`
+	Max          float64         `json:"max" yaml:"max"`
+	Log          bool            `json:"log,omitempty" yaml:"log,omitempty"`
+	Params       []float64       `json:"params,omitempty" yaml:"params,omitempty,flow"`
+	Components   []invdistr.Spec `json:"components,omitempty" yaml:"components,omitempty"`
+	Categories   []string        `json:"categories,omitempty" yaml:"categories,omitempty,flow"`
+	Units        string          `json:"units,omitempty" yaml:"units,omitempty"`
+	Description  string          `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ParamOf returns the sampling-plan file entry of Sampler s
+func ParamOf(s *Sampler) (Param, error) {
+	p := Param{Name: s.Name, Distribution: s.Dist.String(), Min: s.Rmin, Max: s.Rmax, Units: s.Units, Description: s.Description}
+	switch s.Dist {
+	case Constant:
+		p.Max = s.Rmin // the value sampled; plan files hold constants as min = max
+	case Categorical:
+		p.Min, p.Max = 0., 0.
+		p.Categories = s.Categories
+		p.Params = s.Weights
+	case Mapped:
+		sp, err := invdistr.SpecOf(s.Map.Distr)
+		if err != nil {
+			return p, fmt.Errorf("parameter '%s': %w", s.Name, err)
+		}
+		p.Distribution, p.Params, p.Components, p.Log = sp.Kind, sp.Params, sp.Components, s.Map.Log
+		p.Min, p.Max = s.Map.Low, s.Map.High
+		if s.Map.Log {
+			p.Min, p.Max = math.Pow(10., s.Map.Low), math.Pow(10., s.Map.High)
+		}
+	}
+	return p, nil
+}
+
+// Sampler returns the Sampler described by the sampling-plan file entry
+func (p Param) Sampler() (*Sampler, error) {
+	if p.Name == "" {
+		return nil, fmt.Errorf("parameter name missing")
+	}
+	var s *Sampler
+	bad := func(format string, a ...interface{}) error {
+		return fmt.Errorf("parameter '%s': "+format, append([]interface{}{p.Name}, a...)...)
+	}
+	switch p.Distribution {
+	case "constant":
+		if p.Max != 0. && p.Max != p.Min {
+			return nil, bad("constant requires min = max (or max omitted), given %v, %v: %w", p.Min, p.Max, ErrInvalidBounds)
+		}
+		s = &Sampler{Dist: Constant, Rmin: p.Min, Rmax: p.Min}
+	case "linear", "log-linear":
+		if p.Min > p.Max {
+			return nil, bad("invalid range: min > max (%v > %v): %w", p.Min, p.Max, ErrInvalidBounds)
+		}
+		s = &Sampler{Dist: Linear, Rmin: p.Min, Rmax: p.Max}
+		if p.Distribution == "log-linear" {
+			if p.Min <= 0. {
+				return nil, bad("log-linear range must be positive, given %v, %v: %w", p.Min, p.Max, ErrInvalidBounds)
+			}
+			s.Dist = LogLinear
+		}
+	case "integer":
+		if p.Min > p.Max || p.Min != math.Trunc(p.Min) || p.Max != math.Trunc(p.Max) {
+			return nil, bad("invalid integer range %v, %v: %w", p.Min, p.Max, ErrInvalidBounds)
+		}
+		s = &Sampler{Dist: Integer, Rmin: p.Min, Rmax: p.Max}
+	case "categorical":
+		if len(p.Categories) == 0 {
+			return nil, bad("no categories given: %w", ErrDimensionMismatch)
+		}
+		if len(p.Params) > 0 {
+			if len(p.Params) != len(p.Categories) {
+				return nil, bad("%d weights given for %d categories: %w", len(p.Params), len(p.Categories), ErrDimensionMismatch)
+			}
+			if _, err := invdistr.NewCategorical(p.Params); err != nil {
+				return nil, bad("%w", err)
+			}
+		}
+		s = &Sampler{Dist: Categorical, Rmin: 0., Rmax: float64(len(p.Categories) - 1), Categories: p.Categories}
+		if len(p.Params) > 0 {
+			s.Weights = p.Params
+		}
+	case "":
+		return nil, bad("distribution missing: %w", ErrUnknownDistribution)
+	default:
+		if _, ok := invdistr.Kinds[p.Distribution]; !ok {
+			return nil, bad("unknown distribution '%s': %w", p.Distribution, ErrUnknownDistribution)
+		}
+		d, err := invdistr.Spec{Kind: p.Distribution, Params: p.Params, Components: p.Components}.Mapper()
+		if err != nil {
+			return nil, bad("%w", err)
+		}
+		m := &invdistr.Map{Low: p.Min, High: p.Max, Log: p.Log, Distr: d}
+		switch {
+		case p.Min == 0. && p.Max == 0.:
+			m.Low, m.High = 0., 1.
+		case p.Min >= p.Max:
+			return nil, bad("invalid range: min >= max (%v >= %v): %w", p.Min, p.Max, ErrInvalidBounds)
+		case p.Log && p.Min <= 0.:
+			return nil, bad("log range must be positive, given %v, %v: %w", p.Min, p.Max, ErrInvalidBounds)
+		case p.Log:
+			m.Low, m.High = math.Log10(p.Min), math.Log10(p.Max)
+		}
+		s = &Sampler{Dist: Mapped, Map: m}
+		s.Rmin, s.Rmax = mapRange(m)
+	}
+	s.Name, s.Units, s.Description = p.Name, p.Units, p.Description
+	return s, nil
+}
+
+// newPlan returns the Set built from sampling-plan entries ps, found at lines ln of file fp
+func newPlan(fp string, ps []Param, ln []int) (*Set, error) {
+	if len(ps) == 0 {
+		return nil, fmt.Errorf("sampler.ReadPlan %s: no parameters found", fp)
+	}
+	ss, names := make([]*Sampler, len(ps)), make(map[string]int, len(ps))
+	for i, p := range ps {
+		if j, ok := names[p.Name]; ok && p.Name != "" {
+			return nil, fmt.Errorf("sampler.ReadPlan %s: line %d: parameter '%s' already defined on line %d", fp, ln[i], p.Name, ln[j])
+		}
+		names[p.Name] = i
+		s, err := p.Sampler()
+		if err != nil {
+			return nil, fmt.Errorf("sampler.ReadPlan %s: line %d: %w", fp, ln[i], err)
+		}
+		ss[i] = s
+	}
+	return NewSet(ss), nil
+}
+
+// params returns the sampling-plan entries of Set s; derived and tied parameters, transforms,
+// constraints and groups have no entries, such that writing a Set holding any returns an error
+// rather than a plan that silently samples differently
+func (s *Set) params() ([]Param, error) {
+	for _, c := range []struct {
+		n    int
+		what string
+	}{{len(s.Derived), "derived or tied parameters"}, {len(s.Transforms), "transforms"}, {len(s.Constraints), "constraints"}, {len(s.Groups), "groups"}} {
+		if c.n > 0 {
+			return nil, fmt.Errorf("sampler.WritePlan: Set holds %d %s: %w", c.n, c.what, ErrUnsupported)
+		}
+	}
+	ps := make([]Param, len(s.Samplers))
+	for i, m := range s.Samplers {
+		p, err := ParamOf(m)
+		if err != nil {
+			return nil, fmt.Errorf("sampler.WritePlan: %w", err)
+		}
+		ps[i] = p
+	}
+	return ps, nil
+}
+
+// ReadPlan reads a sampling plan (Set) from a JSON (.json), YAML (.yaml, .yml) or CSV (.csv) file
+func ReadPlan(fp string) (*Set, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(fp)) {
+	case ".json":
+		return readPlanJSON(fp, f)
+	case ".yaml", ".yml":
+		return readPlanYAML(fp, f)
+	case ".csv":
+		return readPlanCSV(fp, f)
+	}
+	return nil, fmt.Errorf("sampler.ReadPlan: unknown file type %s", fp)
+}
+
+// WritePlan writes the sampling plan (Set) to a JSON (.json), YAML (.yaml, .yml) or CSV (.csv) file
+func (s *Set) WritePlan(fp string) error {
+	var write func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(fp)) {
+	case ".json":
+		write = s.WritePlanJSON
+	case ".yaml", ".yml":
+		write = s.WritePlanYAML
+	case ".csv":
+		write = s.WritePlanCSV
+	default:
+		return fmt.Errorf("sampler.WritePlan: unknown file type %s", fp)
+	}
+	if _, err := s.params(); err != nil {
+		return err
+	}
+	f, err := os.Create(fp)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadPlanJSON reads a sampling plan from a JSON array of parameters
+func ReadPlanJSON(r io.Reader) (*Set, error) { return readPlanJSON("(json)", r) }
+
+func readPlanJSON(fp string, r io.Reader) (*Set, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	line := func(off int64) int { // line of the first non-space character at or following offset
+		for off < int64(len(b)) && strings.ContainsRune(" \t\r\n,", rune(b[off])) {
+			off++
+		}
+		return bytes.Count(b[:off], []byte("\n")) + 1
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+		return nil, fmt.Errorf("sampler.ReadPlan %s: line %d: expecting an array of parameters", fp, line(dec.InputOffset()))
+	}
+	var ps []Param
+	var ln []int
+	for dec.More() {
+		l := line(dec.InputOffset())
+		var p Param
+		if err := dec.Decode(&p); err != nil {
+			if se, ok := err.(*json.SyntaxError); ok {
+				l = line(se.Offset)
+			}
+			return nil, fmt.Errorf("sampler.ReadPlan %s: line %d: parameter %d: %w", fp, l, len(ps)+1, err)
+		}
+		ps = append(ps, p)
+		ln = append(ln, l)
+	}
+	return newPlan(fp, ps, ln)
+}
+
+// WritePlanJSON writes the sampling plan as a JSON array of parameters
+func (s *Set) WritePlanJSON(w io.Writer) error {
+	ps, err := s.params()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ps)
+}
+
+// ReadPlanYAML reads a sampling plan from a YAML sequence of parameters
+func ReadPlanYAML(r io.Reader) (*Set, error) { return readPlanYAML("(yaml)", r) }
+
+func readPlanYAML(fp string, r io.Reader) (*Set, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("sampler.ReadPlan %s: %w", fp, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("sampler.ReadPlan %s: line %d: expecting a sequence of parameters", fp, doc.Line)
+	}
+	seq := doc.Content[0]
+	ps, ln := make([]Param, len(seq.Content)), make([]int, len(seq.Content))
+	for i, n := range seq.Content {
+		ln[i] = n.Line
+		if err := n.Decode(&ps[i]); err != nil {
+			return nil, fmt.Errorf("sampler.ReadPlan %s: line %d: parameter %d: %w", fp, n.Line, i+1, err)
+		}
+	}
+	return newPlan(fp, ps, ln)
+}
+
+// WritePlanYAML writes the sampling plan as a YAML sequence of parameters
+func (s *Set) WritePlanYAML(w io.Writer) error {
+	ps, err := s.params()
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(ps); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// planColumns are the columns of a CSV sampling plan; list-valued columns (params, categories)
+// are separated by semicolons. Only name and distribution are required.
+var planColumns = []string{"name", "distribution", "min", "max", "log", "params", "categories", "units", "description"}
+
+// ReadPlanCSV reads a sampling plan from a CSV table with a header row naming the columns
+// (name, distribution, min, max, log, params, categories, units, description); mixtures are not supported
+func ReadPlanCSV(r io.Reader) (*Set, error) { return readPlanCSV("(csv)", r) }
+
+func readPlanCSV(fp string, r io.Reader) (*Set, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	hdr, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("sampler.ReadPlan %s: %w", fp, err)
+	}
+	col := make(map[string]int, len(hdr))
+	for i, h := range hdr {
+		h = strings.ToLower(strings.TrimSpace(h))
+		found := false
+		for _, c := range planColumns {
+			found = found || c == h
+		}
+		if !found {
+			return nil, fmt.Errorf("sampler.ReadPlan %s: line 1: unknown column '%s'", fp, h)
+		}
+		col[h] = i
+	}
+	for _, c := range planColumns[:2] {
+		if _, ok := col[c]; !ok {
+			return nil, fmt.Errorf("sampler.ReadPlan %s: line 1: column '%s' missing", fp, c)
+		}
+	}
+	var ps []Param
+	var ln []int
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		l, _ := cr.FieldPos(0)
+		if err != nil {
+			return nil, fmt.Errorf("sampler.ReadPlan %s: %w", fp, err)
+		}
+		field := func(c string) string {
+			if i, ok := col[c]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		p := Param{Name: field("name"), Distribution: field("distribution"), Units: field("units"), Description: field("description")}
+		bad := func(c string, err error) error {
+			return fmt.Errorf("sampler.ReadPlan %s: line %d: parameter '%s': invalid %s '%s': %w", fp, l, p.Name, c, field(c), err)
+		}
+		for _, c := range []struct {
+			name string
+			v    *float64
+		}{{"min", &p.Min}, {"max", &p.Max}} {
+			if f := field(c.name); f != "" {
+				if *c.v, err = strconv.ParseFloat(f, 64); err != nil {
+					return nil, bad(c.name, err)
+				}
+			}
+		}
+		if f := field("log"); f != "" {
+			if p.Log, err = strconv.ParseBool(f); err != nil {
+				return nil, bad("log", err)
+			}
+		}
+		if f := field("params"); f != "" {
+			for _, v := range strings.Split(f, ";") {
+				x, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+				if err != nil {
+					return nil, bad("params", err)
+				}
+				p.Params = append(p.Params, x)
+			}
+		}
+		if f := field("categories"); f != "" {
+			for _, v := range strings.Split(f, ";") {
+				p.Categories = append(p.Categories, strings.TrimSpace(v))
+			}
+		}
+		ps = append(ps, p)
+		ln = append(ln, l)
+	}
+	return newPlan(fp, ps, ln)
+}
+
+// WritePlanCSV writes the sampling plan as a CSV table
+func (s *Set) WritePlanCSV(w io.Writer) error {
+	ps, err := s.params()
+	if err != nil {
+		return err
+	}
+	join := func(n int, f func(i int) string) string {
+		a := make([]string, n)
+		for i := range a {
+			a[i] = f(i)
+		}
+		return strings.Join(a, ";")
+	}
+	ftoa := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	cw := csv.NewWriter(w)
+	cw.Write(planColumns)
+	for _, p := range ps {
+		if len(p.Components) > 0 {
+			return fmt.Errorf("sampler.WritePlanCSV: parameter '%s': mixture distributions cannot be written to CSV", p.Name)
+		}
+		cw.Write([]string{p.Name, p.Distribution, ftoa(p.Min), ftoa(p.Max), strconv.FormatBool(p.Log),
+			join(len(p.Params), func(i int) string { return ftoa(p.Params[i]) }),
+			join(len(p.Categories), func(i int) string { return p.Categories[i] }),
+			p.Units, p.Description})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,157 @@
+package sampler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/maseology/montecarlo/invdistr"
+)
+
+// TestWritePlanUnsupported checks that Sets holding entries without a plan-file form are not written
+func TestWritePlanUnsupported(t *testing.T) {
+	set := func() *Set {
+		return NewSet([]*Sampler{New("a", Linear, 0., 1.), New("b", Linear, 0., 1.), New("c", Linear, 0., 1.)})
+	}
+	var b bytes.Buffer
+	if err := set().WritePlanJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	for name, add := range map[string]func(s *Set) error{
+		"derived":    func(s *Set) error { return s.Derive("d", "a + b") },
+		"tied":       func(s *Set) error { return s.Tie("d", "a") },
+		"transform":  func(s *Set) error { return s.Order("a", "b") },
+		"constraint": func(s *Set) error { return s.Constrain("a < b") },
+		"group":      func(s *Set) error { return s.AddGroup("g", "c") },
+	} {
+		s := set()
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range []func(*bytes.Buffer) error{
+			func(b *bytes.Buffer) error { return s.WritePlanJSON(b) },
+			func(b *bytes.Buffer) error { return s.WritePlanYAML(b) },
+			func(b *bytes.Buffer) error { return s.WritePlanCSV(b) },
+		} {
+			if err := w(&b); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("%s: error = %v", name, err)
+			}
+		}
+	}
+}
+
+// planSet returns a Set holding a parameter of every Sampler distribution and several invdistr kinds
+func planSet(t *testing.T, mixture bool) *Set {
+	mk := func(d invdistr.Distribution, err error) invdistr.Distribution {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	n := mk(invdistr.NewNormal(5., 2.))
+	ss := []*Sampler{
+		New("const", Constant, 2., 3.),
+		New("lin", Linear, -1., 4.),
+		New("loglin", LogLinear, 1e-4, 10.),
+		NewInteger("int", -2, 7),
+		NewCategorical("cat", []string{"sand", "silt", "clay"}, nil),
+		NewCategorical("wcat", []string{"a", "b"}, []float64{1., 3.}),
+		NewMapped("tri", &invdistr.Map{Low: 1., High: 9., Distr: mk(invdistr.NewTriangleE(.25))}),
+		NewMapped("normal", &invdistr.Map{Low: 0., High: 1., Distr: n}),
+		NewMapped("logbeta", &invdistr.Map{Low: -3., High: 1., Log: true, Distr: mk(invdistr.NewBeta(2., 3.))}),
+		NewMapped("johnsonb", &invdistr.Map{Low: 0., High: 2., Distr: mk(invdistr.NewJohnsonBPeak(.3, 3.))}),
+		NewMapped("poisson", &invdistr.Map{Low: 0., High: 1., Distr: mk(invdistr.NewPoisson(3.))}),
+	}
+	if mixture {
+		ss = append(ss, NewMapped("mixture", &invdistr.Map{Low: 0., High: 1., Distr: mk(invdistr.NewMixture([]float64{1., 2.}, n, mk(invdistr.NewGumbel(10., 2.))))}))
+	}
+	ss[1].Units, ss[1].Description = "m", "a linear, parameter"
+	return NewSet(ss)
+}
+
+// TestPlanRoundTrip checks that every distribution written to a plan file of every format reads back the same Sampler
+func TestPlanRoundTrip(t *testing.T) {
+	for _, f := range []struct {
+		name    string
+		write   func(*Set, io.Writer) error
+		read    func(io.Reader) (*Set, error)
+		mixture bool
+	}{
+		{"json", (*Set).WritePlanJSON, ReadPlanJSON, true},
+		{"yaml", (*Set).WritePlanYAML, ReadPlanYAML, true},
+		{"csv", (*Set).WritePlanCSV, ReadPlanCSV, false},
+	} {
+		s := planSet(t, f.mixture)
+		var b bytes.Buffer
+		if err := f.write(s, &b); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		r, err := f.read(&b)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", f.name, err, b.String())
+		}
+		if len(r.Samplers) != len(s.Samplers) || r.Ndim != s.Ndim {
+			t.Fatalf("%s: %d parameters read, %d written", f.name, len(r.Samplers), len(s.Samplers))
+		}
+		for i, m := range s.Samplers {
+			q := r.Samplers[i]
+			if q.Name != m.Name || q.Dist != m.Dist || q.Units != m.Units || q.Description != m.Description || q.IsLog() != m.IsLog() {
+				t.Errorf("%s: parameter %+v read as %+v", f.name, m, q)
+				continue
+			}
+			for _, u := range []float64{0., .05, .3, .5, .7, .95, 1.} {
+				if x, y := m.Sample(u), q.Sample(u); math.Abs(x-y) > 1e-12*math.Max(1., math.Abs(x)) && !(math.IsInf(x, 0) && x == y) {
+					t.Errorf("%s: '%s' Sample(%v) = %v, read back as %v", f.name, m.Name, u, x, y)
+				}
+			}
+		}
+	}
+	var b bytes.Buffer
+	if err := planSet(t, true).WritePlanCSV(&b); err == nil {
+		t.Error("csv: mixture written")
+	}
+}
+
+// TestPlanErrorLines checks that plan-file validation errors report the line of the offending parameter
+func TestPlanErrorLines(t *testing.T) {
+	for _, c := range []struct {
+		name, plan string
+		read       func(io.Reader) (*Set, error)
+		line       int
+	}{
+		{"json range", `[
+  {"name": "a", "distribution": "linear", "min": 0, "max": 1},
+  {"name": "b", "distribution": "linear", "min": 2, "max": 1}
+]`, ReadPlanJSON, 3},
+		{"json unknown", `[
+  {"name": "a", "distribution": "linear", "min": 0, "max": 1},
+
+  {"name": "b",
+   "distribution": "wobbly"}
+]`, ReadPlanJSON, 4},
+		{"json duplicate", `[{"name": "a", "distribution": "linear", "max": 1},
+{"name": "a", "distribution": "linear", "max": 1}]`, ReadPlanJSON, 2},
+		{"yaml constant", `- name: a
+  distribution: linear
+  max: 1
+- name: b
+  distribution: constant
+  min: 1
+  max: 2
+`, ReadPlanYAML, 4},
+		{"yaml params", `- {name: a, distribution: triangle, params: [.5]}
+- {name: b, distribution: normal, params: [0]}
+`, ReadPlanYAML, 2},
+		{"csv integer", "name,distribution,min,max\na,linear,0,1\n# comment\nb,integer,0.5,3\n", ReadPlanCSV, 4},
+		{"csv number", "name,distribution,min,max\na,linear,0,x\n", ReadPlanCSV, 2},
+	} {
+		_, err := c.read(strings.NewReader(c.plan))
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf(": line %d:", c.line)) {
+			t.Errorf("%s: error = %v, want line %d", c.name, err, c.line)
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package sampler
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"math"
 
 	mm "github.com/maseology/mmaths"
+	"github.com/maseology/montecarlo/invdistr"
 )
 
 // Distribution enum type
@@ -14,11 +18,42 @@ const (
 	Constant Distribution = iota
 	Linear
 	LogLinear
+	Integer
+	Categorical
+	Mapped
 )
 
 // String needed to return a Distribution type as string
 func (d Distribution) String() string {
-	return [...]string{"constant", "linear", "log-linear"}[d]
+	return [...]string{"constant", "linear", "log-linear", "integer", "categorical", "mapped"}[d]
+}
+
+// MarshalJSON returns the Distribution name
+func (d Distribution) MarshalJSON() ([]byte, error) {
+	if d < Constant || d > Mapped {
+		return nil, fmt.Errorf("sampler.Distribution: unknown distribution %d", int(d))
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON sets the Distribution from its name (or enum value)
+func (d *Distribution) UnmarshalJSON(b []byte) error {
+	var i int
+	if err := json.Unmarshal(b, &i); err == nil && Distribution(i) >= Constant && Distribution(i) <= Mapped {
+		*d = Distribution(i)
+		return nil
+	}
+	var n string
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	for i := Constant; i <= Mapped; i++ {
+		if i.String() == n {
+			*d = i
+			return nil
+		}
+	}
+	return fmt.Errorf("sampler.Distribution: unknown distribution %s", string(b))
 }
 
 // Sampler is a general struct used
@@ -26,38 +61,360 @@ type Sampler struct {
 	Dist       Distribution
 	Rmin, Rmax float64
 	Name       string
+	Categories []string      `json:",omitempty"` // Categorical only: category names
+	Weights    []float64     `json:",omitempty"` // Categorical only: relative category weights (nil for equally likely)
+	Map        *invdistr.Map `json:",omitempty"` // Mapped only: any invdistr distribution (Rmin, Rmax hold its nominal range)
+
+	Units, Description string `json:",omitempty"`
+}
+
+// UnmarshalJSON sets the Sampler from JSON, returning an error when a Mapped Sampler has no distribution Map
+func (s *Sampler) UnmarshalJSON(b []byte) error {
+	type sampler Sampler // without methods, avoiding recursion
+	var t sampler
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	if t.Dist == Mapped && (t.Map == nil || t.Map.Distr == nil) {
+		return fmt.Errorf("Sampler.UnmarshalJSON error: %s has no distribution Map: %w", t.Name, ErrUnknownDistribution)
+	}
+	*s = Sampler(t)
+	return nil
 }
 
 // New Sampler constructor
 func New(name string, d Distribution, rangeMin, rangeMax float64) *Sampler {
+	s, err := NewE(name, d, rangeMin, rangeMax)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return s
+}
+
+// NewE Sampler constructor, returning an error for an invalid range or distribution
+func NewE(name string, d Distribution, rangeMin, rangeMax float64) (*Sampler, error) {
 	if rangeMin > rangeMax {
-		log.Fatalf("Sampler.New error: invalid input range for %s: min > max\n", name)
+		return nil, fmt.Errorf("Sampler.New error: invalid input range for %s: min > max: %w", name, ErrInvalidBounds)
 	}
 	switch d {
 	case Constant:
 		if rangeMin != rangeMax {
 			log.Printf("Sampler.New warning: %s set to a constant value of %f\n", name, rangeMin)
 		}
+	case Linear:
+	case Integer:
+		if rangeMin != math.Trunc(rangeMin) || rangeMax != math.Trunc(rangeMax) {
+			return nil, fmt.Errorf("Sampler.New error: invalid input range for %s (%s distribution) (min = %f; max = %f): bounds must be integers: %w", name, d, rangeMin, rangeMax, ErrInvalidBounds)
+		}
 	case LogLinear:
 		if rangeMin <= 0. || rangeMax <= 0. {
-			log.Fatalf("Sampler.New error: invalid input range for %s (%s distribution) (min = %f; max = %f\n", name, d, rangeMin, rangeMax)
+			return nil, fmt.Errorf("Sampler.New error: invalid input range for %s (%s distribution) (min = %f; max = %f): %w", name, d, rangeMin, rangeMax, ErrInvalidBounds)
 		}
 	default:
+		return nil, fmt.Errorf("Sampler.New error: %s distribution of %s requires its own constructor: %w", d, name, ErrUnknownDistribution)
+	}
+	return &Sampler{Dist: d, Rmin: rangeMin, Rmax: rangeMax, Name: name}, nil
+}
+
+// NewInteger Sampler constructor of equally-likely integers in [rangeMin, rangeMax]
+func NewInteger(name string, rangeMin, rangeMax int) *Sampler {
+	s, err := NewIntegerE(name, rangeMin, rangeMax)
+	if err != nil {
+		log.Fatalln(err)
 	}
-	return &Sampler{Dist: d, Rmin: rangeMin, Rmax: rangeMax, Name: name}
+	return s
+}
+
+// NewIntegerE Sampler constructor of equally-likely integers, returning an error for an invalid range
+func NewIntegerE(name string, rangeMin, rangeMax int) (*Sampler, error) {
+	if rangeMin > rangeMax {
+		return nil, fmt.Errorf("Sampler.NewInteger error: invalid input range for %s: min > max: %w", name, ErrInvalidBounds)
+	}
+	return &Sampler{Dist: Integer, Rmin: float64(rangeMin), Rmax: float64(rangeMax), Name: name}, nil
+}
+
+// NewCategorical Sampler constructor of named categories with relative weights
+// (nil weights for equally-likely categories). Samples return the category index.
+func NewCategorical(name string, categories []string, weights []float64) *Sampler {
+	s, err := NewCategoricalE(name, categories, weights)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return s
+}
+
+// NewCategoricalE Sampler constructor of named categories, returning an error for invalid weights
+func NewCategoricalE(name string, categories []string, weights []float64) (*Sampler, error) {
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("Sampler.NewCategorical error: no categories given for %s: %w", name, ErrDimensionMismatch)
+	}
+	if weights != nil {
+		if len(weights) != len(categories) {
+			return nil, fmt.Errorf("Sampler.NewCategorical error: %d weights given for %d categories of %s: %w", len(weights), len(categories), name, ErrDimensionMismatch)
+		}
+		s := 0.
+		for _, w := range weights {
+			if w < 0. || math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, fmt.Errorf("Sampler.NewCategorical error: invalid weight %v given for %s: %w", w, name, ErrInvalidShape)
+			}
+			s += w
+		}
+		if s <= 0. {
+			return nil, fmt.Errorf("Sampler.NewCategorical error: weights of %s sum to zero: %w", name, ErrInvalidShape)
+		}
+	}
+	c := make([]string, len(categories))
+	copy(c, categories)
+	var w []float64
+	if weights != nil {
+		w = make([]float64, len(weights))
+		copy(w, weights)
+	}
+	return &Sampler{Dist: Categorical, Rmin: 0., Rmax: float64(len(c) - 1), Name: name, Categories: c, Weights: w}, nil
 }
 
 // Sample returns a value from the distribution based on a U[0,1] sample
 func (s *Sampler) Sample(u float64) float64 {
+	v, err := s.SampleE(u)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return v
+}
+
+// SampleE returns a value from the distribution based on a U[0,1] sample,
+// returning an error for an unknown distribution
+func (s *Sampler) SampleE(u float64) (float64, error) {
 	switch s.Dist {
 	case Constant:
-		return s.Rmin
+		return s.Rmin, nil
 	case Linear:
-		return mm.LinearTransform(s.Rmin, s.Rmax, u)
+		return mm.LinearTransform(s.Rmin, s.Rmax, u), nil
 	case LogLinear:
-		return mm.LogLinearTransform(s.Rmin, s.Rmax, u)
+		return mm.LogLinearTransform(s.Rmin, s.Rmax, u), nil
+	case Integer:
+		m, err := s.MappingE()
+		if err != nil {
+			return -9999., err
+		}
+		return m.P(u), nil
+	case Categorical:
+		return float64(s.category(u)), nil
+	case Mapped:
+		if s.Map == nil || s.Map.Distr == nil {
+			return -9999., fmt.Errorf("Sampler.Sample error: %s has no distribution Map: %w", s.Name, ErrUnknownDistribution)
+		}
+		return s.Map.P(u), nil
 	default:
-		log.Fatalln("Sampler.Sample error: unknown distribution used")
-		return -9999.
+		return -9999., fmt.Errorf("Sampler.Sample error: unknown distribution used for %s: %w", s.Name, ErrUnknownDistribution)
+	}
+}
+
+// NewMapped Sampler constructor from any invdistr distribution Map (including log-space).
+// Rmin and Rmax are set to the range of the Map; for unbounded distributions
+// the 0.1 and 99.9 percentiles are used instead.
+func NewMapped(name string, m *invdistr.Map) *Sampler {
+	s, err := NewMappedE(name, m)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return s
+}
+
+// NewMappedE Sampler constructor from any invdistr distribution Map, returning an error for an invalid Map
+func NewMappedE(name string, m *invdistr.Map) (*Sampler, error) {
+	if m == nil || m.Distr == nil {
+		return nil, fmt.Errorf("Sampler.NewMapped error: no distribution given for %s: %w", name, ErrUnknownDistribution)
+	}
+	if m.High < m.Low {
+		return nil, fmt.Errorf("Sampler.NewMapped error: invalid input range for %s: low > high: %w", name, ErrInvalidBounds)
+	}
+	rmin, rmax := mapRange(m)
+	return &Sampler{Dist: Mapped, Rmin: rmin, Rmax: rmax, Name: name, Map: m}, nil
+}
+
+// mapRange returns the range of Map m, or the 0.1 and 99.9 percentiles when unbounded
+func mapRange(m *invdistr.Map) (float64, float64) {
+	rmin, rmax := m.P(0.), m.P(1.)
+	if math.IsInf(rmin, 0) || math.IsNaN(rmin) {
+		rmin = m.P(.001)
+	}
+	if math.IsInf(rmax, 0) || math.IsNaN(rmax) {
+		rmax = m.P(.999)
+	}
+	return rmin, rmax
+}
+
+// Mapping returns the Sampler distribution as an invdistr.Map
+func (s *Sampler) Mapping() *invdistr.Map {
+	m, err := s.MappingE()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return m
+}
+
+// MappingE returns the Sampler distribution as an invdistr.Map, returning an error
+// for an unknown distribution, a Mapped Sampler without a Map or an invalid range
+func (s *Sampler) MappingE() (*invdistr.Map, error) {
+	switch s.Dist {
+	case Constant:
+		return &invdistr.Map{Low: s.Rmin, High: s.Rmin, Distr: &invdistr.Uniform{}}, nil
+	case Linear:
+		return &invdistr.Map{Low: s.Rmin, High: s.Rmax, Distr: &invdistr.Uniform{}}, nil
+	case LogLinear:
+		return &invdistr.Map{Low: math.Log10(s.Rmin), High: math.Log10(s.Rmax), Log: true, Distr: &invdistr.Uniform{}}, nil
+	case Integer:
+		if s.Rmin != math.Trunc(s.Rmin) || s.Rmax != math.Trunc(s.Rmax) {
+			return nil, fmt.Errorf("Sampler.Mapping error: integer range of %s (min = %f; max = %f) must have integer bounds: %w", s.Name, s.Rmin, s.Rmax, ErrInvalidBounds)
+		}
+		d, err := invdistr.NewDiscreteUniform(int(s.Rmin), int(s.Rmax))
+		if err != nil {
+			return nil, fmt.Errorf("Sampler.Mapping error: %s: %w", s.Name, err)
+		}
+		return &invdistr.Map{Low: 0., High: 1., Distr: d}, nil
+	case Categorical:
+		w := s.Weights
+		if w == nil {
+			w = make([]float64, len(s.Categories))
+			for i := range w {
+				w[i] = 1.
+			}
+		}
+		d, err := invdistr.NewCategorical(w)
+		if err != nil {
+			return nil, fmt.Errorf("Sampler.Mapping error: %s: %w", s.Name, err)
+		}
+		return &invdistr.Map{Low: 0., High: 1., Distr: d}, nil
+	case Mapped:
+		if s.Map == nil || s.Map.Distr == nil {
+			return nil, fmt.Errorf("Sampler.Mapping error: %s has no distribution Map: %w", s.Name, ErrUnknownDistribution)
+		}
+		return s.Map, nil
+	default:
+		return nil, fmt.Errorf("Sampler.Mapping error: unknown distribution used for %s: %w", s.Name, ErrUnknownDistribution)
+	}
+}
+
+// Unsample returns the U[0,1] sample that maps to parameter value v (the inverse of Sample)
+func (s *Sampler) Unsample(v float64) float64 {
+	u, err := s.UnsampleE(v)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return u
+}
+
+// UnsampleE returns the U[0,1] sample that maps to parameter value v, returning an error when the Sampler has no Mapping
+func (s *Sampler) UnsampleE(v float64) (float64, error) {
+	m, err := s.MappingE()
+	if err != nil {
+		return math.NaN(), err
+	}
+	return m.Unsample(v), nil
+}
+
+// attainable returns an error unless v is a value the Sampler can return, i.e., v is within its
+// range, finite (and an integer or category index when discrete) such that Sample(Unsample(v)) = v
+func (s *Sampler) attainable(v float64) error {
+	u, err := s.UnsampleE(v)
+	if err != nil {
+		return err
+	}
+	x, err := s.SampleE(u)
+	if err != nil {
+		return err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) || u < 0. || u > 1. || math.Abs(x-v) > 1e-6*math.Max(1., math.Abs(v)) {
+		return fmt.Errorf("'%s' cannot take value %v: %w", s.Name, v, ErrInvalidBounds)
+	}
+	return nil
+}
+
+// CDF returns the cumulative probability of parameter value v
+func (s *Sampler) CDF(v float64) float64 {
+	f, err := s.CDFE(v)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return f
+}
+
+// CDFE returns the cumulative probability of parameter value v, returning an error when the Sampler has no Mapping
+func (s *Sampler) CDFE(v float64) (float64, error) {
+	m, err := s.MappingE()
+	if err != nil {
+		return math.NaN(), err
+	}
+	return m.CDF(v), nil
+}
+
+// PDF returns the (prior) probability density of parameter value v
+func (s *Sampler) PDF(v float64) float64 {
+	f, err := s.PDFE(v)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return f
+}
+
+// PDFE returns the (prior) probability density of parameter value v, returning an error when the Sampler has no Mapping
+func (s *Sampler) PDFE(v float64) (float64, error) {
+	m, err := s.MappingE()
+	if err != nil {
+		return math.NaN(), err
+	}
+	return m.PDF(v), nil
+}
+
+// IsLog returns true if the Sampler is distributed in (base-10) log space
+func (s *Sampler) IsLog() bool {
+	return s.Dist == LogLinear || (s.Dist == Mapped && s.Map != nil && s.Map.Log)
+}
+
+// SampleInt returns an integer value (or category index) from the distribution based on a U[0,1] sample
+func (s *Sampler) SampleInt(u float64) int {
+	return int(math.Round(s.Sample(u)))
+}
+
+// Category returns the category name from a Categorical distribution based on a U[0,1] sample
+func (s *Sampler) Category(u float64) string {
+	c, err := s.CategoryE(u)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return c
+}
+
+// CategoryE returns the category name based on a U[0,1] sample, returning an error when the Sampler is not Categorical
+func (s *Sampler) CategoryE(u float64) (string, error) {
+	if s.Dist != Categorical || len(s.Categories) == 0 {
+		return "", fmt.Errorf("Sampler.Category error: %s is not categorical: %w", s.Name, ErrUnknownDistribution)
+	}
+	return s.Categories[s.category(u)], nil
+}
+
+// category returns the index of the category whose cumulative weight interval contains u,
+// such that stratified samples remain stratified among categories
+func (s *Sampler) category(u float64) int {
+	n := len(s.Categories)
+	if s.Weights == nil {
+		return int(math.Min(math.Floor(u*float64(n)), float64(n-1)))
+	}
+	t := 0.
+	for _, w := range s.Weights {
+		t += w
+	}
+	c, last := 0., 0
+	for i, w := range s.Weights {
+		if w <= 0. {
+			continue
+		}
+		c += w / t
+		if u < c {
+			return i
+		}
+		last = i
 	}
+	return last
 }
@@ -0,0 +1,138 @@
+package sampler
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/maseology/montecarlo/invdistr"
+)
+
+// TestSamplerJSON checks that Mapped Samplers survive a JSON round trip and are rejected without a Map
+func TestSamplerJSON(t *testing.T) {
+	j, err := invdistr.NewJohnsonBPeak(.25, 3.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapped("k", &invdistr.Map{Low: -2., High: 1., Log: true, Distr: j})
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r Sampler
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsLog() || r.Map.Distr.(*invdistr.JohnsonB).Mode() != .25 {
+		t.Errorf("Sampler %s restored as %+v", b, r)
+	}
+	for _, in := range []string{`{"Dist":"mapped","Name":"k"}`, `{"Dist":5,"Name":"k","Map":null}`} {
+		if err := json.Unmarshal([]byte(in), &r); !errors.Is(err, ErrUnknownDistribution) {
+			t.Errorf("Sampler %s: error = %v", in, err)
+		}
+	}
+}
+
+// TestSamplerUnsample checks that Unsample inverts Sample for every Sampler distribution:
+// Unsample(Sample(u)) = u when continuous, Sample(Unsample(v)) = v when discrete
+func TestSamplerUnsample(t *testing.T) {
+	j, err := invdistr.NewJohnsonBPeak(.3, 3.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := invdistr.NewNormal(5., 2.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := invdistr.NewPoisson(2.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	continuous := []*Sampler{
+		New("linear", Linear, -2., 3.),
+		New("log-linear", LogLinear, .01, 100.),
+		NewMapped("johnsonb", &invdistr.Map{Low: 1., High: 4., Distr: j}),
+		NewMapped("log johnsonb", &invdistr.Map{Low: -2., High: 1., Log: true, Distr: j}),
+		NewMapped("normal", &invdistr.Map{Low: 0., High: 1., Distr: n}),
+	}
+	for _, s := range continuous {
+		for _, u := range []float64{.001, .1, .5, .9, .999} {
+			if got := s.Unsample(s.Sample(u)); math.Abs(got-u) > 1e-7 {
+				t.Errorf("%s: Unsample(Sample(%v)) = %v", s.Name, u, got)
+			}
+		}
+	}
+	discrete := []*Sampler{
+		New("constant", Constant, 3., 3.),
+		NewInteger("integer", -1, 4),
+		NewCategorical("categorical", []string{"a", "b", "c"}, nil),
+		NewCategorical("weighted", []string{"a", "b", "c", "d"}, []float64{1., 0., 2., 3.}),
+		NewMapped("poisson", &invdistr.Map{Low: 0., High: 1., Distr: p}),
+	}
+	for _, s := range discrete {
+		for _, u := range []float64{.001, .1, .3, .5, .7, .9, .999} {
+			v := s.Sample(u)
+			if got := s.Sample(s.Unsample(v)); got != v {
+				t.Errorf("%s: Sample(Unsample(%v)) = %v", s.Name, v, got)
+			}
+		}
+	}
+}
+
+// TestSamplerErrors checks that the error-returning variants report, rather than exit on, invalid Samplers
+func TestSamplerErrors(t *testing.T) {
+	for _, s := range []*Sampler{{Dist: Mapped, Name: "nomap"}, {Dist: Distribution(9), Name: "unknown"}} {
+		if _, err := s.MappingE(); !errors.Is(err, ErrUnknownDistribution) {
+			t.Errorf("%s: MappingE error = %v", s.Name, err)
+		}
+		if _, err := s.UnsampleE(1.); err == nil {
+			t.Errorf("%s: UnsampleE accepted", s.Name)
+		}
+		if _, err := s.CDFE(1.); err == nil {
+			t.Errorf("%s: CDFE accepted", s.Name)
+		}
+		if _, err := s.PDFE(1.); err == nil {
+			t.Errorf("%s: PDFE accepted", s.Name)
+		}
+		if _, err := NewSet([]*Sampler{New("a", Linear, 0., 1.), s}).UnsampleE([]float64{.5, 1.}); err == nil {
+			t.Errorf("%s: Set.UnsampleE accepted", s.Name)
+		}
+	}
+	if _, err := New("a", Linear, 0., 1.).CategoryE(.5); !errors.Is(err, ErrUnknownDistribution) {
+		t.Errorf("CategoryE of a linear Sampler: error = %v", err)
+	}
+	if c, err := NewCategorical("c", []string{"x", "y"}, nil).CategoryE(.75); err != nil || c != "y" {
+		t.Errorf("CategoryE(.75) = %s, %v", c, err)
+	}
+	s := NewSet([]*Sampler{New("a", Linear, 0., 1.), New("b", Linear, 0., 1.)})
+	if _, err := s.CDFE([]float64{.5}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Set.CDFE of one value: error = %v", err)
+	}
+	if _, err := s.LogPDFE([]float64{.5}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Set.LogPDFE of one value: error = %v", err)
+	}
+}
+
+// TestSamplerInteger checks that Integer samplers require integer bounds, and that Sample and Mapping share their support
+func TestSamplerInteger(t *testing.T) {
+	for _, b := range [][2]float64{{.5, 3.}, {0., 3.7}} {
+		if _, err := NewE("i", Integer, b[0], b[1]); !errors.Is(err, ErrInvalidBounds) {
+			t.Errorf("NewE(Integer, %v, %v): error = %v", b[0], b[1], err)
+		}
+		s := &Sampler{Dist: Integer, Rmin: b[0], Rmax: b[1], Name: "i"}
+		if _, err := s.SampleE(.5); !errors.Is(err, ErrInvalidBounds) {
+			t.Errorf("SampleE of integer range %v: error = %v", b, err)
+		}
+	}
+	s, err := NewE("i", Integer, -2., 3.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := s.Mapping()
+	for u := 0.; u <= 1.; u += 1. / 64. {
+		if v := s.Sample(u); v != m.P(u) || v < -2. || v > 3. || v != math.Trunc(v) {
+			t.Errorf("Sample(%v) = %v, Mapping().P = %v", u, v, m.P(u))
+		}
+	}
+}
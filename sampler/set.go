@@ -1,9 +1,25 @@
 package sampler
 
-// Set holds a set of Samplers (i.e., a sampling plan)
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync/atomic"
+)
+
+// Set holds a set of Samplers (i.e., a sampling plan), where Ndim is the number of
+// (independently) sampled parameters, excluding frozen groups; derived parameters are appended to the samples
 type Set struct {
-	Samplers []*Sampler
-	Ndim     int
+	Samplers    []*Sampler
+	Ndim        int
+	Derived     []*Derived    `json:",omitempty"` // parameters computed from others
+	Transforms  []*Transform  `json:",omitempty"` // constraints enforced by transform
+	Constraints []*Constraint `json:",omitempty"` // constraints enforced by rejection
+	Groups      []*Group      `json:",omitempty"` // named groups of parameters that can be frozen
+
+	prog *program
+	lazy atomic.Value // *program compiled by program() when prog is nil
+	rej  *rejections
 }
 
 // NewSet constructs a new sampling set
@@ -15,20 +31,142 @@ func NewSet(s []*Sampler) *Set {
 	return &Set{Samplers: ss, Ndim: len(ss)}
 }
 
-// Sample returns sample from U^n
+// Sample returns sample from U^n (the reduced space when groups are frozen) of all
+// Sampler parameters, followed by any derived parameters.
+// Constraints enforced by rejection are not tested (see SampleE and Feasible).
 func (s *Set) Sample(u []float64) []float64 {
-	v := make([]float64, s.Ndim)
-	for i, uu := range u {
-		v[i] = s.Samplers[i].Sample(uu)
+	if len(s.Derived) == 0 && len(s.Transforms) == 0 && s.Ndim == len(s.Samplers) {
+		v := make([]float64, s.Ndim)
+		for i, uu := range u {
+			v[i] = s.Samplers[i].Sample(uu)
+		}
+		return v
+	}
+	p, err := s.program()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	uu, err := s.full(u)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	v, err := s.apply(p, uu)
+	if err != nil {
+		log.Fatalln(err)
 	}
 	return v
 }
 
-// ParameterNames returns the names of Sampler parameters
-func (s *Set) ParameterNames() []string {
-	p := make([]string, s.Ndim)
+// SampleE returns sample from U^n, followed by any derived parameters, returning an error when
+// the length of u does not match the Set dimension or a Sampler fails. Samples violating a constraint
+// are returned with an error wrapping ErrRejected; rejections are counted (see Rejections).
+func (s *Set) SampleE(u []float64) ([]float64, error) {
+	uu, err := s.full(u)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.program()
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.apply(p, uu)
+	if err != nil {
+		return nil, err
+	}
+	if ok, c := s.feasible(p, v); !ok {
+		return v, fmt.Errorf("Set.Sample: '%s': %w", c, ErrRejected)
+	}
+	return v, nil
+}
+
+// Unsample returns the point in U^n (the reduced space when groups are frozen) that maps to
+// parameter values v (the inverse of Sample, marginally: transforms are not inverted and
+// derived parameters are ignored)
+func (s *Set) Unsample(v []float64) []float64 {
+	u, err := s.UnsampleE(v)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return u
+}
+
+// UnsampleE returns the point in U^n that maps to parameter values v, returning an error when
+// fewer values than Samplers are given or a Sampler has no Mapping
+func (s *Set) UnsampleE(v []float64) ([]float64, error) {
+	if len(v) < len(s.Samplers) {
+		return nil, fmt.Errorf("Set.Unsample error: %d values given for %d parameters: %w", len(v), len(s.Samplers), ErrDimensionMismatch)
+	}
+	u := make([]float64, len(s.Samplers))
 	for i, m := range s.Samplers {
-		p[i] = m.Name
+		uu, err := m.UnsampleE(v[i])
+		if err != nil {
+			return nil, err
+		}
+		u[i] = uu
 	}
-	return p
+	if s.Ndim != len(s.Samplers) {
+		return s.Reduce(u), nil
+	}
+	return u, nil
+}
+
+// CDF returns the marginal cumulative probabilities of parameter values v
+func (s *Set) CDF(v []float64) []float64 {
+	f, err := s.CDFE(v)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return f
+}
+
+// CDFE returns the marginal cumulative probabilities of parameter values v, returning an error when
+// fewer values than Samplers are given or a Sampler has no Mapping
+func (s *Set) CDFE(v []float64) ([]float64, error) {
+	if len(v) < len(s.Samplers) {
+		return nil, fmt.Errorf("Set.CDF error: %d values given for %d parameters: %w", len(v), len(s.Samplers), ErrDimensionMismatch)
+	}
+	f := make([]float64, len(s.Samplers))
+	for i, m := range s.Samplers {
+		ff, err := m.CDFE(v[i])
+		if err != nil {
+			return nil, err
+		}
+		f[i] = ff
+	}
+	return f, nil
+}
+
+// LogPDF returns the log of the (prior) joint probability density of parameter values v,
+// assuming independent parameters; constant, frozen and derived parameters are ignored
+func (s *Set) LogPDF(v []float64) float64 {
+	l, err := s.LogPDFE(v)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return l
+}
+
+// LogPDFE returns the log of the (prior) joint probability density of parameter values v, returning
+// an error when fewer values than Samplers are given or a Sampler has no Mapping
+func (s *Set) LogPDFE(v []float64) (float64, error) {
+	if len(v) < len(s.Samplers) {
+		return math.NaN(), fmt.Errorf("Set.LogPDF error: %d values given for %d parameters: %w", len(v), len(s.Samplers), ErrDimensionMismatch)
+	}
+	l := 0.
+	for _, i := range s.active() {
+		if s.Samplers[i].Dist == Constant {
+			continue
+		}
+		f, err := s.Samplers[i].PDFE(v[i])
+		if err != nil {
+			return math.NaN(), err
+		}
+		l += math.Log(f)
+	}
+	return l, nil
+}
+
+// ParameterNames returns the names of Sampler parameters, followed by those derived
+func (s *Set) ParameterNames() []string {
+	return s.names()
 }
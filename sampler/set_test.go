@@ -0,0 +1,119 @@
+package sampler
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/maseology/montecarlo/invdistr"
+)
+
+// TestSetUnsample checks that Set.Unsample inverts Set.Sample, in both the full and the reduced space
+func TestSetUnsample(t *testing.T) {
+	tr, err := invdistr.NewTriangleE(.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSet([]*Sampler{
+		New("a", Linear, 0., 10.),
+		New("b", LogLinear, 1e-3, 1.),
+		NewMapped("c", &invdistr.Map{Low: 2., High: 6., Distr: tr}),
+		NewInteger("d", 1, 5),
+	})
+	u := []float64{.1, .35, .8, .5}
+	near := func(got, want []float64) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if math.Abs(got[i]-want[i]) > 1e-9 {
+				return false
+			}
+		}
+		return true
+	}
+	if got := s.Unsample(s.Sample(u)); !near(got, u) {
+		t.Errorf("Unsample(Sample(%v)) = %v", u, got)
+	}
+	if err := s.AddGroup("g", "b", "d"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Freeze("g", nil); err != nil {
+		t.Fatal(err)
+	}
+	r := []float64{.1, .8}
+	v := s.Sample(r)
+	if got := s.Unsample(v); !near(got, r) {
+		t.Errorf("frozen: Unsample(Sample(%v)) = %v", r, got)
+	}
+	if v[1] != s.Samplers[1].Sample(.5) || v[3] != s.Samplers[3].Sample(.5) {
+		t.Errorf("frozen: Sample(%v) = %v, frozen values not held", r, v)
+	}
+}
+
+// TestSetProgramCache checks that a Set read without its compiled program compiles once
+func TestSetProgramCache(t *testing.T) {
+	s := NewSet([]*Sampler{New("a", Linear, 0., 1.), New("b", Linear, 0., 1.)})
+	if err := s.Derive("c", "a + b"); err != nil {
+		t.Fatal(err)
+	}
+	r := &Set{Samplers: s.Samplers, Ndim: s.Ndim, Derived: s.Derived} // as read from file, not compiled
+	if v := r.Sample([]float64{.25, .5}); len(v) != 3 || v[2] != .75 {
+		t.Errorf("Sample = %v", v)
+	}
+	p, ok := r.lazy.Load().(*program)
+	if !ok {
+		t.Fatal("compiled program not cached")
+	}
+	if q, _ := r.program(); q != p {
+		t.Error("program recompiled")
+	}
+}
+
+// TestSetFreezeValues checks that Freeze rejects values its Samplers cannot return
+func TestSetFreezeValues(t *testing.T) {
+	n, err := invdistr.NewNormal(5., 2.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSet([]*Sampler{
+		New("a", Linear, 0., 10.),
+		New("b", LogLinear, 1e-3, 1.),
+		NewInteger("c", 1, 5),
+		NewMapped("d", &invdistr.Map{Low: 0., High: 1., Distr: n}),
+		NewCategorical("e", []string{"x", "y"}, nil),
+	})
+	for _, g := range []string{"a", "b", "c", "d", "e"} {
+		if err := s.AddGroup(g, g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, c := range []struct {
+		g  string
+		ok []float64
+		no []float64
+	}{
+		{"a", []float64{0., 3.3, 10.}, []float64{-.1, 10.5, math.NaN()}},
+		{"b", []float64{1e-3, .05, 1.}, []float64{0., -1., 2.}},
+		{"c", []float64{1., 3., 5.}, []float64{0., 2.5, 6.}},
+		{"d", []float64{-3., 5., 13.}, []float64{math.Inf(1), math.NaN()}},
+		{"e", []float64{0., 1.}, []float64{-1., .5, 2.}},
+	} {
+		for _, v := range c.ok {
+			if err := s.Freeze(c.g, []float64{v}); err != nil {
+				t.Errorf("Freeze(%s, %v): %v", c.g, v, err)
+			}
+			if u := s.Expand(make([]float64, s.Ndim)); u[s.samplerIndex(c.g)] < 0. || u[s.samplerIndex(c.g)] > 1. {
+				t.Errorf("Freeze(%s, %v): expanded to %v", c.g, v, u)
+			}
+			if err := s.Unfreeze(c.g); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, v := range c.no {
+			if err := s.Freeze(c.g, []float64{v}); !errors.Is(err, ErrInvalidBounds) {
+				t.Errorf("Freeze(%s, %v): error = %v", c.g, v, err)
+			}
+		}
+	}
+}
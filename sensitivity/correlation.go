@@ -0,0 +1,105 @@
+// correlation.go computes correlation-based sensitivity measures
+// relating sampled parameters to model evaluations.
+// see Saltelli, A., K. Chan, and E.M. Scott, 2000. Sensitivity Analysis. John Wiley & Sons, Ltd. 475pp.
+
+package sensitivity
+
+import (
+	"fmt"
+	"math"
+)
+
+// Coefficient holds a sensitivity measure along with its t-statistic
+// and two-sided p-value (testing the null hypothesis of no correlation)
+type Coefficient struct {
+	Value, T, P float64
+}
+
+// Pearson returns the Pearson product-moment correlation coefficient of x and y
+func Pearson(x, y []float64) (Coefficient, error) {
+	if len(x) != len(y) {
+		return Coefficient{}, fmt.Errorf("sensitivity.Pearson: %d x values given with %d y values", len(x), len(y))
+	}
+	return significance(correlation(x, y), len(x)-2), nil
+}
+
+// Spearman returns the Spearman rank correlation coefficient of x and y
+func Spearman(x, y []float64) (Coefficient, error) {
+	if len(x) != len(y) {
+		return Coefficient{}, fmt.Errorf("sensitivity.Spearman: %d x values given with %d y values", len(x), len(y))
+	}
+	return significance(correlation(rank(x), rank(y)), len(x)-2), nil
+}
+
+// PRCC returns the partial rank correlation coefficients of every parameter
+// (columns of u; rows are samples, as returned by GenerateSamples or sampler.Set.Sample)
+// with objective vector f, removing the linear effect of all other parameters.
+// Parameters with no variance (e.g., constants) are returned as NaN.
+func PRCC(u [][]float64, f []float64) ([]Coefficient, error) {
+	x, err := columns(u, f)
+	if err != nil {
+		return nil, err
+	}
+	for j := range x {
+		x[j] = rank(x[j])
+	}
+	return partial(x, rank(f))
+}
+
+// PCC returns the (unranked) partial correlation coefficients of every parameter with f.
+func PCC(u [][]float64, f []float64) ([]Coefficient, error) {
+	x, err := columns(u, f)
+	if err != nil {
+		return nil, err
+	}
+	return partial(x, f)
+}
+
+func partial(x [][]float64, y []float64) ([]Coefficient, error) {
+	act := active(x)
+	c := make([]Coefficient, len(x))
+	for j := range c {
+		c[j] = Coefficient{math.NaN(), math.NaN(), math.NaN()}
+	}
+	if len(act) == 0 {
+		return c, nil
+	}
+
+	// inverse of the correlation matrix of [x_act, y]
+	m := len(act)
+	r := make([][]float64, m+1)
+	for i := range r {
+		r[i] = make([]float64, m+1)
+		r[i][i] = 1.
+	}
+	for i := 0; i < m; i++ {
+		for k := i + 1; k < m; k++ {
+			r[i][k] = correlation(x[act[i]], x[act[k]])
+			r[k][i] = r[i][k]
+		}
+		r[i][m] = correlation(x[act[i]], y)
+		r[m][i] = r[i][m]
+	}
+	ri, err := inverse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	df := len(y) - 2 - (m - 1)
+	for i, j := range act {
+		c[j] = significance(-ri[i][m]/math.Sqrt(ri[i][i]*ri[m][m]), df)
+	}
+	return c, nil
+}
+
+// significance returns the t-test of correlation coefficient r having df degrees of freedom
+func significance(r float64, df int) Coefficient {
+	if math.IsNaN(r) || df < 1 {
+		return Coefficient{r, math.NaN(), math.NaN()}
+	}
+	if math.Abs(r) >= 1. {
+		return Coefficient{r, math.Copysign(math.Inf(1), r), 0.}
+	}
+	t := r * math.Sqrt(float64(df)/(1.-r*r))
+	return Coefficient{r, t, studentTwoSided(t, float64(df))}
+}
@@ -0,0 +1,79 @@
+package sensitivity
+
+import "math"
+
+// SRC returns the standardized regression coefficients of every parameter
+// (columns of u; rows are samples) regressed linearly against objective vector f,
+// along with the coefficient of determination (R²) of the regression.
+// SRCs are only a valid sensitivity measure when R² is high (i.e., > 0.7).
+// Parameters with no variance (e.g., constants) are returned as NaN.
+func SRC(u [][]float64, f []float64) ([]Coefficient, float64, error) {
+	x, err := columns(u, f)
+	if err != nil {
+		return nil, math.NaN(), err
+	}
+	return regress(x, f)
+}
+
+// SRRC returns the standardized rank regression coefficients and the R² of the rank regression.
+// Used in place of SRC when the model response is non-linear, yet monotonic.
+func SRRC(u [][]float64, f []float64) ([]Coefficient, float64, error) {
+	x, err := columns(u, f)
+	if err != nil {
+		return nil, math.NaN(), err
+	}
+	for j := range x {
+		x[j] = rank(x[j])
+	}
+	return regress(x, rank(f))
+}
+
+func regress(x [][]float64, y []float64) ([]Coefficient, float64, error) {
+	act := active(x)
+	c := make([]Coefficient, len(x))
+	for j := range c {
+		c[j] = Coefficient{math.NaN(), math.NaN(), math.NaN()}
+	}
+	if len(act) == 0 {
+		return c, math.NaN(), nil
+	}
+
+	// normal equations in correlation form: Rxx b = rxy
+	m := len(act)
+	rxx, rxy := make([][]float64, m), make([]float64, m)
+	for i := 0; i < m; i++ {
+		rxx[i] = make([]float64, m)
+		rxx[i][i] = 1.
+	}
+	for i := 0; i < m; i++ {
+		for k := i + 1; k < m; k++ {
+			rxx[i][k] = correlation(x[act[i]], x[act[k]])
+			rxx[k][i] = rxx[i][k]
+		}
+		rxy[i] = correlation(x[act[i]], y)
+	}
+	ri, err := inverse(rxx)
+	if err != nil {
+		return nil, math.NaN(), err
+	}
+
+	b, r2 := make([]float64, m), 0.
+	for i := 0; i < m; i++ {
+		for k := 0; k < m; k++ {
+			b[i] += ri[i][k] * rxy[k]
+		}
+		r2 += b[i] * rxy[i]
+	}
+
+	df := len(y) - m - 1
+	for i, j := range act {
+		c[j].Value = b[i]
+		if df < 1 {
+			continue
+		}
+		se := math.Sqrt((1. - r2) / float64(df) * ri[i][i])
+		c[j].T = b[i] / se
+		c[j].P = studentTwoSided(c[j].T, float64(df))
+	}
+	return c, r2, nil
+}
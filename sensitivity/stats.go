@@ -0,0 +1,147 @@
+package sensitivity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/maseology/montecarlo/invdistr"
+)
+
+// columns transposes sample matrix u (n samples by p parameters) into p columns of length n
+func columns(u [][]float64, f []float64) ([][]float64, error) {
+	n := len(u)
+	if n != len(f) {
+		return nil, fmt.Errorf("sensitivity: %d samples given with %d objective function values", n, len(f))
+	}
+	if n == 0 {
+		return nil, errors.New("sensitivity: no samples given")
+	}
+	p := len(u[0])
+	x := make([][]float64, p)
+	for j := range x {
+		x[j] = make([]float64, n)
+	}
+	for i, r := range u {
+		if len(r) != p {
+			return nil, fmt.Errorf("sensitivity: sample %d has %d parameters, expecting %d", i, len(r), p)
+		}
+		for j, v := range r {
+			x[j][i] = v
+		}
+	}
+	return x, nil
+}
+
+// active returns the indices of columns having non-zero variance
+func active(x [][]float64) []int {
+	a := make([]int, 0, len(x))
+	for j, c := range x {
+		for _, v := range c[1:] {
+			if v != c[0] {
+				a = append(a, j)
+				break
+			}
+		}
+	}
+	return a
+}
+
+func correlation(x, y []float64) float64 {
+	n := float64(len(x))
+	mx, my := 0., 0.
+	for i := range x {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= n
+	my /= n
+	sxy, sxx, syy := 0., 0., 0.
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0. || syy == 0. {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// rank returns the (1-based) ranks of x, ties are given their average rank
+func rank(x []float64) []float64 {
+	n := len(x)
+	ix := make([]int, n)
+	for i := range ix {
+		ix[i] = i
+	}
+	sort.SliceStable(ix, func(i, j int) bool { return x[ix[i]] < x[ix[j]] })
+	r := make([]float64, n)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && x[ix[j]] == x[ix[i]] {
+			j++
+		}
+		rr := float64(i+j+1) / 2. // average of ranks i+1..j
+		for k := i; k < j; k++ {
+			r[ix[k]] = rr
+		}
+		i = j
+	}
+	return r
+}
+
+// inverse returns the inverse of square matrix a using Gauss-Jordan elimination with partial pivoting
+func inverse(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, 2*n)
+		copy(m[i], a[i])
+		m[i][n+i] = 1.
+	}
+	for c := 0; c < n; c++ {
+		p := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(m[r][c]) > math.Abs(m[p][c]) {
+				p = r
+			}
+		}
+		if math.Abs(m[p][c]) < 1e-12 {
+			return nil, errors.New("sensitivity: singular matrix, parameters are collinear")
+		}
+		m[c], m[p] = m[p], m[c]
+		d := m[c][c]
+		for k := range m[c] {
+			m[c][k] /= d
+		}
+		for r := 0; r < n; r++ {
+			if r == c || m[r][c] == 0. {
+				continue
+			}
+			f := m[r][c]
+			for k := range m[r] {
+				m[r][k] -= f * m[c][k]
+			}
+		}
+	}
+	inv := make([][]float64, n)
+	for i := range inv {
+		inv[i] = m[i][n:]
+	}
+	return inv, nil
+}
+
+// studentTwoSided returns the two-sided p-value of statistic t from a Student's t-distribution with df degrees of freedom
+func studentTwoSided(t, df float64) float64 {
+	if math.IsInf(t, 0) {
+		return 0.
+	}
+	st, err := invdistr.NewStudentT(df, 0., 1.)
+	if err != nil {
+		return math.NaN()
+	}
+	return 2. * st.CDF(-math.Abs(t))
+}
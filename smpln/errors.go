@@ -0,0 +1,10 @@
+package smpln
+
+import "errors"
+
+// Errors returned (wrapped) by the sampling plan constructors, test using errors.Is
+var (
+	ErrInvalidSize       = errors.New("invalid sample size")
+	ErrDimensionMismatch = errors.New("dimension mismatch")
+	ErrOutOfRange        = errors.New("value out of range U[0,1)")
+)
@@ -6,6 +6,7 @@
 package smpln
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/maseology/mmaths"
@@ -40,6 +41,22 @@ type HaltonDigitalSequence struct {
 
 // NewHalton allocates a new instance of the LHC
 func NewHalton(n, p int) *HaltonDigitalSequence {
+	hds, err := NewHaltonE(n, p)
+	if err != nil {
+		log.Panicln(err)
+	}
+	return hds
+}
+
+// NewHaltonE allocates a new instance of the Halton sequence, returning an error
+// for an invalid size or for more dimensions than Faure-Lemieux factors available
+func NewHaltonE(n, p int) (*HaltonDigitalSequence, error) {
+	if n < 1 || p < 1 {
+		return nil, fmt.Errorf("smpln.NewHalton: invalid size n, p = %d, %d: %w", n, p, ErrInvalidSize)
+	}
+	if p > len(fls) {
+		return nil, fmt.Errorf("smpln.NewHalton: %d dimensions given, %d supported: %w", p, len(fls), ErrDimensionMismatch)
+	}
 	hds := &HaltonDigitalSequence{
 		U: make([][]float64, p),
 		n: n,
@@ -56,35 +73,21 @@ func NewHalton(n, p int) *HaltonDigitalSequence {
 			// hds.U[j][i] = vanderCorput(i+1, b[j], 1) // original Halton sequence
 			hds.U[j][i] = vanderCorput(i+1, b[j], fls[j]) // Faure-Lemieux generalized Halton sequence (using i+1 to avoid 0,0 returned for small dimensions)
 			if hds.U[j][i] > 1.0 || hds.U[j][i] < 0.0 {
-				log.Panicf("Halton digital sequence error: value out of range U[0,1): %v", hds.U[j][i])
+				return nil, fmt.Errorf("Halton digital sequence error: %v: %w", hds.U[j][i], ErrOutOfRange)
 			}
 		}
 	}
-	return hds
+	return hds, nil
 }
 
 // SampleSize simply returns the number of samples
 func (hds *HaltonDigitalSequence) SampleSize() int { return hds.n }
 
-func vanderCorputSeq(k, b, m int) []float64 {
-	// van der Corput sequence (i.e., radical inverse function of a given base)
-	// see pg. 145 in Lemieux, C., Monte Carlo and Quasi-Monte Carlo Sampling. Springer Science. 2009. 373pp.
-	if b <= 1 || m < 1 {
-		panic("vanderCorput_seq input error")
-	}
-	p := make([]float64, 0, k)
-	for i := 0; i < k; i++ {
-		p = append(p, vanderCorput(i, b, m))
-	}
-	return p
-}
-
+// vanderCorput returns the van der Corput sequence (i.e., radical inverse function of base b),
+// scrambled by multiplicative factor m; requires b > 1 and m >= 1, which always hold for
+// the primes and Faure-Lemieux factors used by NewHaltonE (see TestHaltonFactors)
+// see pg. 145 in Lemieux, C., Monte Carlo and Quasi-Monte Carlo Sampling. Springer Science. 2009. 373pp.
 func vanderCorput(i, b, m int) float64 {
-	// van der Corput sequence (i.e., radical inverse function of base b)
-	// see pg. 145 in Lemieux, C., Monte Carlo and Quasi-Monte Carlo Sampling. Springer Science. 2009. 373pp.
-	if b <= 1 || m < 1 {
-		panic("vanderCorput input error")
-	}
 	inv := 0.
 	ins := 1
 loop:
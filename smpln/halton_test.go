@@ -0,0 +1,35 @@
+package smpln
+
+import (
+	"testing"
+
+	"github.com/maseology/mmaths"
+)
+
+// TestHaltonFactors checks the preconditions of vanderCorput for every dimension NewHaltonE supports:
+// bases are primes greater than one and Faure-Lemieux factors are at least one
+func TestHaltonFactors(t *testing.T) {
+	b := mmaths.Primes(len(fls))
+	if len(b) != len(fls) {
+		t.Fatalf("%d primes returned for %d dimensions", len(b), len(fls))
+	}
+	for j := range fls {
+		if b[j] <= 1 || fls[j] < 1 {
+			t.Errorf("dimension %d: base %d, factor %d", j, b[j], fls[j])
+		}
+	}
+	h, err := NewHaltonE(50, len(fls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j := range h.U {
+		for i, u := range h.U[j] {
+			if u < 0. || u >= 1. {
+				t.Errorf("U[%d][%d] = %v", j, i, u)
+			}
+		}
+	}
+	if _, err := NewHaltonE(10, len(fls)+1); err == nil {
+		t.Error("NewHaltonE: unsupported dimension accepted")
+	}
+}
@@ -33,6 +33,7 @@
 package smpln
 
 import (
+	"fmt"
 	"log"
 	"math/rand"
 )
@@ -47,6 +48,19 @@ type LatinHyperCube struct {
 
 // NewLHC allocates a new instance of the LHC from n samples of p dimensions.
 func NewLHC(rng *rand.Rand, n, p int, midpoint bool) *LatinHyperCube {
+	lhc, err := NewLHCE(rng, n, p, midpoint)
+	if err != nil {
+		log.Panicln(err)
+	}
+	return lhc
+}
+
+// NewLHCE allocates a new instance of the LHC from n samples of p dimensions,
+// returning an error for an invalid size.
+func NewLHCE(rng *rand.Rand, n, p int, midpoint bool) (*LatinHyperCube, error) {
+	if n < 1 || p < 1 {
+		return nil, fmt.Errorf("smpln.NewLHC: invalid size n, p = %d, %d: %w", n, p, ErrInvalidSize)
+	}
 	lhc := &LatinHyperCube{
 		U: make([][]float64, p),
 		n: n,
@@ -55,14 +69,16 @@ func NewLHC(rng *rand.Rand, n, p int, midpoint bool) *LatinHyperCube {
 	for i := range lhc.U {
 		lhc.U[i] = make([]float64, n)
 	}
-	lhc.make(rng, midpoint)
-	return lhc
+	if err := lhc.make(rng, midpoint); err != nil {
+		return nil, err
+	}
+	return lhc, nil
 }
 
 // make builds the sampling plan nxp matrix.
 // Setting midpoint to False adds an additional random jitter
 // to the position of the sample within sample space.
-func (lhc *LatinHyperCube) make(rng *rand.Rand, midpoint bool) {
+func (lhc *LatinHyperCube) make(rng *rand.Rand, midpoint bool) error {
 	nf := float64(lhc.n)
 	w := 1.0 / (2.0 * nf)
 	ks := NewKS(lhc.n, lhc.p)
@@ -75,10 +91,11 @@ func (lhc *LatinHyperCube) make(rng *rand.Rand, midpoint bool) {
 			}
 			lhc.U[j][i] = float64(ks.Z[j][i])/nf + w
 			if lhc.U[j][i] > 1.0 || lhc.U[j][i] < 0.0 {
-				log.Panicf("LHC error: value out of range U[0,1): %v", lhc.U[j][i])
+				return fmt.Errorf("LHC error: %v: %w", lhc.U[j][i], ErrOutOfRange)
 			}
 		}
 	}
+	return nil
 }
 
 // SampleSize simply returns the number of samples